@@ -1,28 +1,146 @@
 package circle
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
 
-func NewKnownError(status, message string) error {
-	return knownError{
-		Status:  status,
-		Message: message,
+	"go.uber.org/zap/zapcore"
+)
+
+// Code is a canonical error category a caller can match on with errors.Is,
+// independent of whatever Message happens to say.
+type Code string
+
+const (
+	CodeUnknown           Code = "unknown"
+	CodeInvalidArgument   Code = "invalid_argument"
+	CodeNotFound          Code = "not_found"
+	CodeAlreadyExists     Code = "already_exists"
+	CodePermissionDenied  Code = "permission_denied"
+	CodeUnauthenticated   Code = "unauthenticated"
+	CodeResourceExhausted Code = "resource_exhausted"
+	CodeUnavailable       Code = "unavailable"
+	CodeInternal          Code = "internal"
+	CodeDeadlineExceeded  Code = "deadline_exceeded"
+)
+
+// httpStatus is the canonical Code -> HTTP status mapping. A Code missing
+// from the table (including the zero Code) falls back to 500.
+var httpStatus = map[Code]int{
+	CodeInvalidArgument:   http.StatusBadRequest,
+	CodeNotFound:          http.StatusNotFound,
+	CodeAlreadyExists:     http.StatusConflict,
+	CodePermissionDenied:  http.StatusForbidden,
+	CodeUnauthenticated:   http.StatusUnauthorized,
+	CodeResourceExhausted: http.StatusTooManyRequests,
+	CodeUnavailable:       http.StatusServiceUnavailable,
+	CodeInternal:          http.StatusInternalServerError,
+	CodeDeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
+// HTTPStatus is the status a dispatcher should respond with for c.
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatus[c]; ok {
+		return status
 	}
+	return http.StatusInternalServerError
+}
+
+// Error is circle's general-purpose error type. Code drives the HTTP status
+// and is what errors.Is matches on; Message is safe to show a caller;
+// Fields holds request-scoped context (a field name, an id) attached via
+// WithField; cause and stack, if present, are for debugging only and never
+// serialize to JSON.
+type Error struct {
+	Code    Code           `json:"code"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+
+	cause error
+	stack string
 }
 
-type knownError struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("[%s] %s: %s", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
 }
 
-func (k knownError) Error() string {
-	return fmt.Sprintf("[Status] %s [Message] %s", k.Status, k.Message)
+// Unwrap exposes cause so errors.Is/errors.As see through e to whatever it
+// wrapped.
+func (e *Error) Unwrap() error {
+	return e.cause
 }
 
-func (k knownError) Is(err error) bool {
-	knownErr, ok := err.(knownError)
+// Is matches on Code alone. The old knownError required exact Status AND
+// Message equality, which broke the moment a message was formatted with
+// different arguments; matching on Code lets errors.Is(err, circle.New(circle.CodeNotFound, ""))
+// find any not-found error regardless of what its Message says.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
 	if !ok {
 		return false
 	}
+	return e.Code == t.Code
+}
 
-	return k.Status == knownErr.Status && k.Message == knownErr.Message
-}
\ No newline at end of file
+// New builds an Error of the given Code.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrapf builds an Error wrapping cause, with cause as Unwrap's target and a
+// formatted Message describing the context in which cause was wrapped.
+func Wrapf(cause error, code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), cause: cause}
+}
+
+// WithStack captures a stack trace at the call site and returns e for
+// chaining. Capturing one isn't free, so it's opt-in rather than automatic
+// on every New/Wrapf call; reserve it for errors worth debugging, not ones
+// raised on a hot path.
+func (e *Error) WithStack() *Error {
+	e.stack = string(debug.Stack())
+	return e
+}
+
+// WithField attaches a piece of request-scoped context to e and returns e
+// for chaining, so callers can build up detail as an error bubbles up
+// without losing the original Message.
+func (e *Error) WithField(key string, value any) *Error {
+	if e.Fields == nil {
+		e.Fields = make(map[string]any, 1)
+	}
+	e.Fields[key] = value
+	return e
+}
+
+// MarshalLogObject lets zap.Any detect Error via zapcore.ObjectMarshaler and
+// render code/message/cause/stack/fields as separate JSON keys instead of
+// collapsing the whole error into one "error" string, so log.Error(err)
+// produces structured output for free.
+func (e *Error) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", string(e.Code))
+	enc.AddString("message", e.Message)
+	if e.cause != nil {
+		enc.AddString("cause", e.cause.Error())
+	}
+	if e.stack != "" {
+		enc.AddString("stack", e.stack)
+	}
+	for k, v := range e.Fields {
+		if err := enc.AddReflected(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewKnownError preserves the original two-field constructor for callers
+// that only have a status string and message; new code should prefer New,
+// Wrapf, or one of the well-known Codes above.
+func NewKnownError(status, message string) error {
+	return New(Code(status), message)
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
 	"github.com/stacktasec/circle/internal/kit/zlog"
+	"github.com/stacktasec/circle/kit/hub"
+	"github.com/stacktasec/circle/kit/klog"
 	"go.uber.org/dig"
 	"io/fs"
 	"net/http"
@@ -35,6 +37,9 @@ type app struct {
 	baseGroup     *gin.RouterGroup
 	limitBucket   *ratelimit.Bucket
 	loadValue     atomic.Value
+	middlewares   []Middleware
+	grpcActions   map[string]reflectAction
+	hubs          map[string]hub.Hub
 }
 
 func NewApp(opts ...AppOption) *app {
@@ -60,6 +65,18 @@ func (a *app) Map(groups ...*versionGroup) {
 	}
 }
 
+// MapHub mounts h's WebSocket and SSE transports at path+"/ws" and
+// path+"/sse" the next time build runs.
+func (a *app) MapHub(path string, h hub.Hub) {
+	if a.hubs == nil {
+		a.hubs = make(map[string]hub.Hub)
+	}
+	if _, ok := a.hubs[path]; ok {
+		panic("duplicated hub path")
+	}
+	a.hubs[path] = h
+}
+
 func (a *app) Provide(constructors ...any) {
 	for _, item := range constructors {
 		if err := a.container.Provide(item); err != nil {
@@ -76,6 +93,7 @@ func (a *app) build() {
 		r.Use(func(c *gin.Context) {
 			value := a.loadValue.Load()
 			if value == true {
+				zlog.Warn("request rejected: system overloaded")
 				c.AbortWithStatus(http.StatusServiceUnavailable)
 				return
 			}
@@ -88,6 +106,7 @@ func (a *app) build() {
 		r.Use(func(c *gin.Context) {
 			count := a.limitBucket.TakeAvailable(1)
 			if count == 0 {
+				zlog.Warn("request rejected: rate limit exceeded")
 				c.AbortWithStatus(http.StatusTooManyRequests)
 				return
 			}
@@ -103,6 +122,8 @@ func (a *app) build() {
 
 	a.discovery(r)
 
+	a.mountOpenAPI(r)
+
 	baseGroup := r.Group(a.options.baseURL)
 	a.baseGroup = baseGroup
 
@@ -110,6 +131,12 @@ func (a *app) build() {
 		a.fillGroups(g)
 	}
 
+	for path, h := range a.hubs {
+		h := h
+		r.GET(path+"/ws", func(c *gin.Context) { h.ServeWS(c.Writer, c.Request) })
+		r.GET(path+"/sse", func(c *gin.Context) { h.ServeSSE(c.Writer, c.Request) })
+	}
+
 	r.Use(gzip.Gzip(gzip.DefaultCompression))
 
 	a.engine = r
@@ -129,6 +156,10 @@ func (a *app) Run() {
 		a.watch()
 	}
 
+	if a.options.grpcAddr != "" {
+		go a.RunGRPC(a.options.grpcAddr)
+	}
+
 	httpServer := http.Server{
 		Addr:           a.options.addr,
 		Handler:        a.engine,
@@ -214,6 +245,12 @@ type reflectAction struct {
 	methodData reflect.Value
 	// 请求 返回类型
 	respType string
+	// 跳过中间件链
+	anonymous bool
+	// 调用该方法所需的 UserRole，为空表示不限制
+	requiredRoles []string
+	// 从 OpenAPI 文档中排除
+	omitted bool
 }
 
 func (a *app) fillGroups(vg *versionGroup) {
@@ -241,9 +278,6 @@ func (a *app) fillActions(g *gin.RouterGroup, service any) {
 	for _, action := range actions {
 
 		g.POST(fmt.Sprintf("/%s/%s", action.serviceName, action.methodName), func(c *gin.Context) {
-			if ok := a.handleHeader(c); !ok {
-				return
-			}
 
 			req := action.bindData
 			if err := c.ShouldBind(&req); err != nil {
@@ -257,11 +291,38 @@ func (a *app) fillActions(g *gin.RouterGroup, service any) {
 				return
 			}
 
-			ctx := context.Background()
+			ctx := c.Request.Context()
 
 			reqID := uuid.NewString()
 			ctx = context.WithValue(ctx, keyRequestID, reqID)
-			timeoutCtx, cancel := context.WithTimeout(ctx, a.options.ctxTimeout)
+
+			var claims *JwtClaims
+			if !action.anonymous {
+				var err error
+				ctx, err = a.runMiddlewares(ctx, c.Request.Header)
+				if err != nil {
+					c.AbortWithStatus(http.StatusUnauthorized)
+					return
+				}
+
+				claims, _ = ClaimsFromContext(ctx)
+				if err := checkPermissions(action.requiredRoles, claims); err != nil {
+					c.AbortWithStatus(http.StatusForbidden)
+					return
+				}
+			}
+
+			fields := []klog.Field{
+				klog.String("request_id", reqID),
+				klog.String("service", action.serviceName),
+				klog.String("method", action.methodName),
+			}
+			if claims != nil {
+				fields = append(fields, klog.String("tenant_id", claims.TenantID), klog.String("user_id", claims.UserID))
+			}
+			ctx = klog.ContextWithFields(ctx, fields...)
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, requestTimeout(c.Request.Header, a.options.ctxTimeout))
 			defer cancel()
 
 			c.Writer.Header().Set(keyRequestID, reqID)
@@ -274,6 +335,11 @@ func (a *app) fillActions(g *gin.RouterGroup, service any) {
 			// 还是原生error
 			errValue := rtnList[1].Interface()
 			if errValue != nil {
+				if errValue == context.Canceled || timeoutCtx.Err() == context.Canceled {
+					c.AbortWithStatus(statusClientClosedRequest)
+					return
+				}
+
 				if errValue == context.DeadlineExceeded {
 					c.AbortWithStatus(http.StatusGatewayTimeout)
 					return
@@ -310,24 +376,3 @@ func (a *app) fillActions(g *gin.RouterGroup, service any) {
 		})
 	}
 }
-
-func (a *app) handleHeader(c *gin.Context) bool {
-	h := c.Request.Header
-
-	if a.options.idInterceptor != nil {
-		if err := a.options.idInterceptor(h); err != nil {
-			c.AbortWithStatus(http.StatusUnauthorized)
-			return false
-		}
-
-		// 隐含：必须有身份 才有权限
-		if a.options.permInterceptor != nil {
-			if err := a.options.permInterceptor(h); err != nil {
-				c.AbortWithStatus(http.StatusForbidden)
-				return false
-			}
-		}
-	}
-
-	return true
-}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type cancelPingRequest struct{}
+
+func (cancelPingRequest) Validate() error { return nil }
+
+type cancelPingResponse struct{}
+
+// cancelPingService blocks until ctx is canceled and closes cancelObserved,
+// letting the test confirm a client disconnect actually reaches the
+// reflected method instead of it running against a detached
+// context.Background().
+type cancelPingService struct{}
+
+var cancelObserved = make(chan struct{})
+
+func (cancelPingService) Wait(ctx context.Context, req cancelPingRequest) (*cancelPingResponse, error) {
+	<-ctx.Done()
+	close(cancelObserved)
+	return nil, ctx.Err()
+}
+
+func NewCancelPingService() *cancelPingService {
+	return &cancelPingService{}
+}
+
+// TestFillActions_ClientDisconnectCancelsContext verifies that fillActions
+// derives the reflected method's context from c.Request.Context(), so a
+// client that closes its connection mid-flight cancels the downstream call
+// instead of leaving it running against context.Background().
+func TestFillActions_ClientDisconnectCancelsContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := NewApp()
+	r := gin.New()
+	g := r.Group("")
+	a.fillActions(g, NewCancelPingService)
+
+	routes := r.Routes()
+	if len(routes) != 1 {
+		t.Fatalf("expected exactly one registered route, got %d", len(routes))
+	}
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+routes[0].Path, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := http.DefaultClient.Do(req)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	select {
+	case <-cancelObserved:
+	case <-time.After(time.Second):
+		t.Fatal("reflected method never observed ctx.Done() after client disconnect")
+	}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stacktasec/circle/internal/kit/zlog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const codecName = "json"
+
+// jsonCodec lets the same bind structs used by the HTTP transport travel
+// over gRPC without a generated .proto, by honoring their json tags.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// buildGRPC mirrors fillActions: every reflectAction produced by makeActions
+// becomes a unary RPC on a ServiceDesc named after the snake-cased service.
+func (a *app) buildGRPC() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(a.grpcIDInterceptor),
+	)
+
+	byService := make(map[string][]reflectAction)
+	a.grpcActions = make(map[string]reflectAction)
+	for _, g := range a.versionGroups {
+		var constructors []any
+		constructors = append(constructors, g.stableConstructors...)
+		constructors = append(constructors, g.betaConstructors...)
+		constructors = append(constructors, g.alphaConstructors...)
+
+		for _, item := range constructors {
+			for _, action := range a.makeActions(item) {
+				byService[action.serviceName] = append(byService[action.serviceName], action)
+				a.grpcActions["/"+action.serviceName+"/"+action.methodName] = action
+			}
+		}
+	}
+
+	for svcName, actions := range byService {
+		server.RegisterService(makeServiceDesc(svcName, actions), nil)
+	}
+
+	return server
+}
+
+func makeServiceDesc(svcName string, actions []reflectAction) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: svcName,
+		HandlerType: (*any)(nil),
+		Metadata:    "circle/core",
+	}
+
+	for _, action := range actions {
+		action := action
+		fullMethod := "/" + svcName + "/" + action.methodName
+
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: action.methodName,
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				// action.bindData is the single reflect.New instance makeActions
+				// stashed on reflectAction at startup; reusing it here would let
+				// concurrent calls to this action - gRPC or HTTP - clobber each
+				// other's request, so every call gets its own.
+				req := reflect.New(reflect.TypeOf(action.bindData).Elem()).Interface()
+				if err := dec(req); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				i := req.(Request)
+				if err := i.Validate(); err != nil {
+					return nil, status.Error(codes.InvalidArgument, err.Error())
+				}
+
+				handler := func(ctx context.Context, req any) (any, error) {
+					reqValue := reflect.ValueOf(req).Elem()
+					rtnList := action.methodData.Call([]reflect.Value{reflect.ValueOf(ctx), reqValue})
+
+					if errValue := rtnList[1].Interface(); errValue != nil {
+						if errValue == context.DeadlineExceeded {
+							return nil, status.Error(codes.DeadlineExceeded, errValue.(error).Error())
+						}
+
+						if known, ok := errValue.(knownError); ok {
+							return nil, status.Error(codes.FailedPrecondition, known.Error())
+						}
+
+						return nil, status.Error(codes.Internal, errValue.(error).Error())
+					}
+
+					return rtnList[0].Interface(), nil
+				}
+
+				// grpc-go hands the registered UnaryInterceptor to us instead of
+				// invoking it itself - without this call a.grpcIDInterceptor (and
+				// the shedding/rate-limit/auth/timeout it wires in) never runs.
+				if interceptor == nil {
+					return handler(ctx, req)
+				}
+
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}
+				return interceptor(ctx, req, info, handler)
+			},
+		})
+	}
+
+	return desc
+}
+
+// grpcIDInterceptor mirrors everything app.build wires around fillActions for
+// HTTP: the same overload-break and rate-limit gates (a.loadValue/a.limitBucket),
+// the same Middleware chain and anonymous/RequirePermissions checks, an
+// X-Request-Timeout-bounded deadline, and an X-Request-ID stamped onto both
+// ctx and the outgoing response metadata.
+func (a *app) grpcIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if a.options.enableOverloadBreak && a.loadValue.Load() == true {
+		zlog.Warn("grpc request rejected: system overloaded")
+		return nil, status.Error(codes.ResourceExhausted, "system overloaded")
+	}
+
+	if a.options.enableRateLimit && a.limitBucket != nil && a.limitBucket.TakeAvailable(1) == 0 {
+		zlog.Warn("grpc request rejected: rate limit exceeded")
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+
+	reqID := uuid.NewString()
+	ctx = context.WithValue(ctx, keyRequestID, reqID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(keyRequestID, reqID))
+
+	action, known := a.grpcActions[info.FullMethod]
+	if !(known && action.anonymous) {
+		// metadata keys arrive lowercased; re-add them so http.Header.Get's
+		// canonical lookups (e.g. "Authorization" in JWT) find them.
+		h := http.Header{}
+		for k, vs := range md {
+			for _, v := range vs {
+				h.Add(k, v)
+			}
+		}
+
+		var err error
+		ctx, err = a.runMiddlewares(ctx, h)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if known {
+			claims, _ := ClaimsFromContext(ctx)
+			if err := checkPermissions(action.requiredRoles, claims); err != nil {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+		}
+	}
+
+	timeout := a.options.ctxTimeout
+	if raw := md.Get(headerRequestTimeout); len(raw) > 0 {
+		if d, err := time.ParseDuration(raw[0]); err == nil && d > 0 && d <= timeout {
+			timeout = d
+		}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return handler(timeoutCtx, req)
+}
+
+func (a *app) RunGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		panic(err)
+	}
+
+	server := a.buildGRPC()
+
+	zlog.Info(fmt.Sprintf("grpc server is listening on %s", addr))
+	if err := server.Serve(lis); err != nil {
+		panic(err)
+	}
+}
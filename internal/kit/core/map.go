@@ -39,6 +39,24 @@ func (a *app) makeActions(constructor any) []reflectAction {
 	pointerValue := reflect.ValueOf(rtn)
 	pointerType := pointerValue.Type()
 
+	var anonymous bool
+	anonymousType := reflect.TypeOf((*AnonymousAttribute)(nil)).Elem()
+	if pointerType.Implements(anonymousType) {
+		anonymous = pointerValue.Interface().(AnonymousAttribute).Anonymous()
+	}
+
+	var requiredRoles map[string][]string
+	requireType := reflect.TypeOf((*RequirePermissions)(nil)).Elem()
+	if pointerType.Implements(requireType) {
+		requiredRoles = pointerValue.Interface().(RequirePermissions).RequirePermissions()
+	}
+
+	var omitted bool
+	omittedType := reflect.TypeOf((*OmittedAttribute)(nil)).Elem()
+	if pointerType.Implements(omittedType) {
+		omitted = pointerValue.Interface().(OmittedAttribute).Omitted()
+	}
+
 	var actions []reflectAction
 	for i := 0; i < pointerType.NumMethod(); i++ {
 		// 获得方法
@@ -79,11 +97,14 @@ func (a *app) makeActions(constructor any) []reflectAction {
 
 		svcName, methodName := a.makeName(pointerType.Elem().Name(), method.Name)
 		action := reflectAction{
-			serviceName: svcName,
-			methodName:  methodName,
-			bindData:    reflect.New(in2).Interface(),
-			methodValue: pointerValue.Method(i),
-			respType:    respType,
+			serviceName:   svcName,
+			methodName:    methodName,
+			bindData:      reflect.New(in2).Interface(),
+			methodData:    pointerValue.Method(i),
+			respType:      respType,
+			anonymous:     anonymous,
+			requiredRoles: requiredRoles[method.Name],
+			omitted:       omitted,
 		}
 
 		actions = append(actions, action)
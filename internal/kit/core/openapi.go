@@ -0,0 +1,209 @@
+package core
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// openAPIDoc is a minimal OpenAPI 3.0 document, just enough to describe the
+// POST-per-action routes fillActions registers.
+type openAPIDoc struct {
+	OpenAPI    string                    `json:"openapi"`
+	Info       openAPIInfo               `json:"info"`
+	Paths      map[string]map[string]any `json:"paths"`
+	Components *openAPIComponents        `json:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	SecuritySchemes map[string]any `json:"securitySchemes"`
+}
+
+// bearerAuthScheme describes the Authorization: Bearer <token> header every
+// Middleware installed via app.Use (JWT in particular) expects; it's attached
+// to the document once a.middlewares is non-empty, and referenced by every
+// non-anonymous operation.
+var bearerAuthScheme = map[string]any{
+	"type":         "http",
+	"scheme":       "bearer",
+	"bearerFormat": "JWT",
+}
+
+var bearerSecurityRequirement = []map[string][]string{{"bearerAuth": {}}}
+
+var errorResponses = map[string]any{
+	"400": openAPIResponse("bad request"),
+	"401": openAPIResponse("unauthorized"),
+	"403": openAPIResponse("forbidden"),
+	"409": openAPIResponse("known error"),
+	"500": openAPIResponse("internal error"),
+	"504": openAPIResponse("timeout"),
+}
+
+func openAPIResponse(desc string) map[string]any {
+	return map[string]any{"description": desc}
+}
+
+// mountOpenAPI walks versionGroups, reflects over every bind struct/response
+// pair makeActions already validated, and serves the resulting document
+// plus a bare-bones Swagger UI under baseURL.
+func (a *app) mountOpenAPI(r *gin.Engine) {
+	r.GET(a.options.baseURL+"/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, a.buildOpenAPI())
+	})
+
+	r.GET(a.options.baseURL+"/docs", func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.String(http.StatusOK, swaggerUIPage(a.options.baseURL+"/openapi.json"))
+	})
+}
+
+func (a *app) buildOpenAPI() openAPIDoc {
+	doc := openAPIDoc{
+		OpenAPI: "3.0.0",
+		Info:    openAPIInfo{Title: "circle", Version: "1"},
+		Paths:   make(map[string]map[string]any),
+	}
+
+	if len(a.middlewares) > 0 {
+		doc.Components = &openAPIComponents{
+			SecuritySchemes: map[string]any{"bearerAuth": bearerAuthScheme},
+		}
+	}
+
+	for _, g := range a.versionGroups {
+		a.describeGroup(doc.Paths, g)
+	}
+
+	return doc
+}
+
+func (a *app) describeGroup(paths map[string]map[string]any, vg *versionGroup) {
+	versions := map[string][]any{
+		fmt.Sprintf("/v%d", vg.mainVersion):      vg.stableConstructors,
+		fmt.Sprintf("/v%dbeta", vg.mainVersion):  vg.betaConstructors,
+		fmt.Sprintf("/v%dalpha", vg.mainVersion): vg.alphaConstructors,
+	}
+
+	for prefix, constructors := range versions {
+		for _, constructor := range constructors {
+			for _, action := range a.makeActions(constructor) {
+				if action.omitted {
+					continue
+				}
+
+				path := a.options.baseURL + prefix + "/" + action.serviceName + "/" + action.methodName
+				operation := map[string]any{
+					"operationId": action.serviceName + "." + action.methodName,
+					"requestBody": map[string]any{
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": describeStruct(reflect.TypeOf(action.bindData).Elem()),
+							},
+						},
+					},
+					"responses": withOKResponse(action),
+				}
+
+				if !action.anonymous && len(a.middlewares) > 0 {
+					operation["security"] = bearerSecurityRequirement
+				}
+
+				paths[path] = map[string]any{"post": operation}
+			}
+		}
+	}
+}
+
+func withOKResponse(action reflectAction) map[string]any {
+	responses := map[string]any{
+		"200": map[string]any{
+			"description": "ok",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": describeResponse(action),
+				},
+			},
+		},
+	}
+
+	for status, resp := range errorResponses {
+		responses[status] = resp
+	}
+
+	return responses
+}
+
+// describeResponse derives the 200 response schema from the reflected
+// method's actual return type instead of a bare {"type":"object"}; stream
+// responses (fs.File) have no JSON shape, so they're described as a binary
+// string per the OpenAPI 3.0 convention.
+func describeResponse(action reflectAction) map[string]any {
+	if action.respType == respTypeStream {
+		return map[string]any{"type": "string", "format": "binary"}
+	}
+
+	return describeStruct(action.methodData.Type().Out(0).Elem())
+}
+
+// describeStruct derives a JSON-Schema object from a bind struct's json tags.
+func describeStruct(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		properties[name] = map[string]any{"type": jsonSchemaType(field.Type)}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+func jsonSchemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func swaggerUIPage(specURL string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><title>circle docs</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist/swagger-ui.css" />
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist/swagger-ui-bundle.js"></script>
+<script>
+window.onload = () => SwaggerUIBundle({url: %q, dom_id: '#swagger-ui'})
+</script>
+</body>
+</html>`, specURL)
+}
@@ -1,7 +1,6 @@
 package core
 
 import (
-	"net/http"
 	"time"
 )
 
@@ -18,9 +17,6 @@ type options struct {
 	baseURL    string
 	ctxTimeout time.Duration
 
-	idInterceptor   func(h http.Header) error
-	permInterceptor func(h http.Header) error
-
 	enableRateLimit bool
 	fillInterval    time.Duration
 	capacity        int64
@@ -29,6 +25,8 @@ type options struct {
 	enableOverloadBreak bool
 	maxCpuPercent       float64
 	maxMemPercent       float64
+
+	grpcAddr string
 }
 
 func (o *options) ensure() {
@@ -91,18 +89,6 @@ func WithCtxTimeout(d time.Duration) AppOption {
 	})
 }
 
-func WithIDInterceptor(i func(h http.Header) error) AppOption {
-	return appOptionFunc(func(opts *options) {
-		opts.idInterceptor = i
-	})
-}
-
-func WithPermInterceptor(p func(h http.Header) error) AppOption {
-	return appOptionFunc(func(opts *options) {
-		opts.permInterceptor = p
-	})
-}
-
 func WithRateLimit(fillInterval time.Duration, capacity, quantum int) AppOption {
 	return appOptionFunc(func(opts *options) {
 		opts.enableRateLimit = true
@@ -119,3 +105,11 @@ func WithOverloadBreak(maxCpu, maxMem float64) AppOption {
 		opts.maxMemPercent = maxMem
 	})
 }
+
+// WithGRPC starts a gRPC listener alongside the HTTP server, exposing the
+// same reflected services as unary RPCs.
+func WithGRPC(addr string) AppOption {
+	return appOptionFunc(func(opts *options) {
+		opts.grpcAddr = addr
+	})
+}
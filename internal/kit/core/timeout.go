@@ -0,0 +1,29 @@
+package core
+
+import (
+	"net/http"
+	"time"
+)
+
+// statusClientClosedRequest mirrors nginx's 499: the client disconnected
+// before the server could finish the request.
+const statusClientClosedRequest = 499
+
+const headerRequestTimeout = "X-Request-Timeout"
+
+// requestTimeout honors the caller-supplied X-Request-Timeout header, capped
+// at ctxTimeout so a client can ask for a tighter deadline but never a
+// looser one.
+func requestTimeout(h http.Header, ctxTimeout time.Duration) time.Duration {
+	raw := h.Get(headerRequestTimeout)
+	if raw == "" {
+		return ctxTimeout
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 || d > ctxTimeout {
+		return ctxTimeout
+	}
+
+	return d
+}
@@ -1,138 +1,44 @@
+// Package zlog is internal/kit/core's own logging call sites' entry point -
+// it used to own its package-global *zap.Logger directly, but now forwards
+// to kit/klog.Logger so those call sites keep compiling unchanged. Unlike
+// the public github.com/stacktasec/circle/log package (which forwards for
+// the same reason but is a deliberate external-compatibility shim), zlog is
+// internal-only: the coupling to kit/klog here is just an ordinary
+// in-module dependency, not a public API commitment. New code in this
+// module should depend on kit/klog directly instead of through this shim.
 package zlog
 
 import (
 	"fmt"
-	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
+	"github.com/stacktasec/circle/kit/klog"
 )
 
-const (
-	levelDebug = "debug"
-	levelInfo  = "info"
-	levelWarn  = "warn"
-	levelError = "error"
-	levelPanic = "panic"
-	levelFatal = "fatal"
-)
-
-type options struct {
-	level string
-}
-
-func (o *options) ensure() {
-	switch o.level {
-	case levelDebug, levelInfo, levelWarn, levelError, levelPanic, levelFatal:
-	default:
-		o.level = levelDebug
-	}
-}
-
-func convert(level string) zapcore.Level {
-	switch level {
-	case "debug":
-		return zapcore.DebugLevel
-	case "info":
-		return zapcore.InfoLevel
-	case "warn":
-		return zapcore.WarnLevel
-	case "error":
-		return zapcore.ErrorLevel
-	case "panic":
-		return zapcore.PanicLevel
-	case "fatal":
-		return zapcore.FatalLevel
-	default:
-		panic("can not convert")
-	}
-}
-
-type LogOption interface {
-	apply(*options)
-}
-
-type logOptionFunc func(opts *options)
-
-func (opt logOptionFunc) apply(opts *options) {
-	opt(opts)
-}
-
-func WithLevel(level string) LogOption {
-	return logOptionFunc(func(opts *options) {
-		opts.level = level
-	})
-}
-
-var (
-	zapLogger  *zap.Logger
-	logOptions *options
-)
-
-func init() {
-	InitLogger()
-}
-
-func InitLogger(opts ...LogOption) {
-	logOptions = &options{}
-
-	for _, opt := range opts {
-		opt.apply(logOptions)
-	}
-
-	logOptions.ensure()
-
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:      "time",
-		LevelKey:     "level",
-		CallerKey:    "caller",
-		MessageKey:   "msg",
-		EncodeLevel:  zapcore.LowercaseColorLevelEncoder,
-		EncodeTime:   zapcore.ISO8601TimeEncoder,
-		EncodeCaller: zapcore.ShortCallerEncoder,
-	}
-
-	config := zap.Config{
-		Encoding:         "console",
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-		Level:            zap.NewAtomicLevelAt(convert(logOptions.level)),
-	}
-
-	logger, _ := config.Build()
-
-	zapLogger = logger.WithOptions(zap.AddCallerSkip(1))
-}
-
-func SyncLogger() error {
-	return zapLogger.Sync()
-}
-
 func Debug(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Debug(msg)
+	klog.Debug(format, a...)
 }
 
 func Info(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Info(msg)
+	klog.Info(format, a...)
 }
 
 func Warn(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Warn(msg)
+	klog.Warn(format, a...)
 }
 
 func Error(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Error(msg)
+	klog.Error(format, a...)
 }
 
 func Panic(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Panic(msg)
+	klog.Error(msg)
+	panic(msg)
 }
 
 func Fatal(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	zapLogger.Fatal(msg)
+	klog.Fatal(format, a...)
+}
+
+func SyncLogger() error {
+	return klog.SyncLogger()
 }
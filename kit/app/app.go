@@ -1,8 +1,13 @@
 package app
 
 import (
+	"context"
 	"github.com/stacktasec/circle/kit/app/gin"
 	"github.com/stacktasec/circle/kit/app/internal"
+	"github.com/stacktasec/circle/kit/app/jwtauth"
+	"github.com/stacktasec/circle/kit/klog"
+	"go.opentelemetry.io/otel/trace"
+	"io"
 	"net/http"
 	"time"
 )
@@ -10,11 +15,17 @@ import (
 type App interface {
 	Provide(constructors ...any)
 	Map(groups ...*internal.VersionGroup)
+	// WriteOpenAPI encodes the OpenAPI document also served at
+	// {BaseURL}/openapi.json to w.
+	WriteOpenAPI(w io.Writer) error
 	Run()
+	Shutdown(ctx context.Context) error
 }
 
 type Request = internal.Request
 
+type Codec = internal.Codec
+
 var _ App = (*gin.App)(nil)
 
 func NewGroup(mainVersion int) *internal.VersionGroup {
@@ -27,13 +38,80 @@ func NewGroup(mainVersion int) *internal.VersionGroup {
 	}
 }
 
+// UserFrom recovers the authenticated caller's JWT claims from ctx, stashed
+// there by the dispatcher for every request an Authenticator identified.
+func UserFrom(ctx context.Context) (jwtauth.Claims, bool) {
+	v, ok := internal.ClaimsFromContext(ctx)
+	if !ok {
+		return jwtauth.Claims{}, false
+	}
+
+	claims, ok := v.(jwtauth.Claims)
+	return claims, ok
+}
+
+// RequestIDFrom recovers the request id the dispatcher generated for the
+// call ctx descends from.
+func RequestIDFrom(ctx context.Context) (string, bool) {
+	return internal.RequestIDFromContext(ctx)
+}
+
+// LoggerFrom returns a Logger carrying the request id/service/method fields
+// the dispatcher attached to ctx.
+func LoggerFrom(ctx context.Context) klog.Logger {
+	return klog.Ctx(ctx)
+}
+
+// MakeKnownError preserves the original two-field constructor; status is
+// carried as-is as the error's Code, so old callers keep compiling but get
+// the 409 fallback status until they migrate to NewError or a Code-specific
+// helper below.
 func MakeKnownError(status, message string) error {
 	return internal.KnownError{
-		Status:  status,
+		Code:    internal.Code(status),
 		Message: message,
 	}
 }
 
+// NewError builds a structured error fillActions reports as
+// {err_code, err_msg, request_id, details} with the HTTP status code maps
+// to, instead of the flat 409-for-everything scheme.
+func NewError(code internal.Code, message string, details ...any) error {
+	return internal.NewError(code, message, details...)
+}
+
+func NewInvalidArgument(message string, details ...any) error {
+	return internal.NewError(internal.CodeInvalidArgument, message, details...)
+}
+
+func NewNotFound(message string, details ...any) error {
+	return internal.NewError(internal.CodeNotFound, message, details...)
+}
+
+func NewAlreadyExists(message string, details ...any) error {
+	return internal.NewError(internal.CodeAlreadyExists, message, details...)
+}
+
+func NewPermissionDenied(message string, details ...any) error {
+	return internal.NewError(internal.CodePermissionDenied, message, details...)
+}
+
+func NewUnauthenticated(message string, details ...any) error {
+	return internal.NewError(internal.CodeUnauthenticated, message, details...)
+}
+
+func NewResourceExhausted(message string, details ...any) error {
+	return internal.NewError(internal.CodeResourceExhausted, message, details...)
+}
+
+func NewUnavailable(message string, details ...any) error {
+	return internal.NewError(internal.CodeUnavailable, message, details...)
+}
+
+func NewInternal(message string, details ...any) error {
+	return internal.NewError(internal.CodeInternal, message, details...)
+}
+
 func WithAddr(addr string) internal.AppOption {
 	return internal.OptionFunc(func(opts *internal.Options) {
 		opts.Addr = addr
@@ -48,6 +126,22 @@ func WithTLS(cert, key string) internal.AppOption {
 	})
 }
 
+func WithQUIC(cert, key string) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.EnableQUIC = true
+		opts.Cert = cert
+		opts.Key = key
+	})
+}
+
+// WithH3AltSvc advertises a standalone QUIC endpoint on port via the
+// Alt-Svc header, for operators terminating TLS/HTTP3 in front of this app.
+func WithH3AltSvc(port int) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.H3AltSvcPort = port
+	})
+}
+
 func WithBaseURL(url string) internal.AppOption {
 	return internal.OptionFunc(func(opts *internal.Options) {
 		opts.BaseURL = url
@@ -60,6 +154,15 @@ func WithCtxTimeout(d time.Duration) internal.AppOption {
 	})
 }
 
+// WithMaxBodyBytes caps the request body fillActions will decode for every
+// action; requests over the limit are rejected with 413 before reaching
+// the codec or the handler.
+func WithMaxBodyBytes(n int64) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.MaxBodyBytes = n
+	})
+}
+
 func WithSuffixes(suffixes []string) internal.AppOption {
 	return internal.OptionFunc(func(opts *internal.Options) {
 		opts.Suffixes = suffixes
@@ -72,7 +175,7 @@ func WithIDInterceptor(i func(h http.Header) error) internal.AppOption {
 	})
 }
 
-func WithPermInterceptor(p func(h http.Header) error) internal.AppOption {
+func WithPermInterceptor(p func(h http.Header, route string) error) internal.AppOption {
 	return internal.OptionFunc(func(opts *internal.Options) {
 		opts.PermInterceptor = p
 	})
@@ -87,6 +190,45 @@ func WithRateLimit(fillInterval time.Duration, capacity, quantum int) internal.A
 	})
 }
 
+func WithCodec(codec Codec) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		if opts.Codecs == nil {
+			opts.Codecs = map[string]internal.Codec{}
+		}
+		opts.Codecs[codec.ContentType()] = codec
+	})
+}
+
+// WithMetrics registers Prometheus collectors for request count, latency,
+// in-flight gauge, and bytes in/out, labeled by service/method/version
+// channel/status, and exposes them on /metrics.
+func WithMetrics(namespace string) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.EnableMetrics = true
+		opts.MetricsNamespace = namespace
+	})
+}
+
+// WithTracing starts a span around every reflected action call, propagating
+// W3C traceparent headers into the action's context.Context.
+func WithTracing(tp trace.TracerProvider) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.EnableTracing = true
+		opts.TracerProvider = tp
+	})
+}
+
+// WithOTLPTracing enables tracing without requiring the caller to assemble
+// a TracerProvider themselves: NewApp builds one exporting spans to
+// endpoint over OTLP/gRPC, identifying this process as serviceName.
+func WithOTLPTracing(serviceName, endpoint string) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.EnableTracing = true
+		opts.ServiceName = serviceName
+		opts.OTLPEndpoint = endpoint
+	})
+}
+
 func WithLoadLimit(maxCpu, maxMem float64) internal.AppOption {
 	return internal.OptionFunc(func(opts *internal.Options) {
 		opts.EnableLoadLimit = true
@@ -94,3 +236,19 @@ func WithLoadLimit(maxCpu, maxMem float64) internal.AppOption {
 		opts.MaxMemPercent = maxMem
 	})
 }
+
+// WithAdaptiveShedding layers a latency-driven shedder on top of
+// WithLoadLimit's CPU/mem ceiling: sloLatency is the p95 ceiling that, once
+// breached for breachWindows consecutive one-minute windows, ramps up a
+// probabilistic 503 shed rate. targetRPS and targetLatency give a Little's
+// law target concurrency that is reported at /debug/shed; it does not cap
+// admission.
+func WithAdaptiveShedding(targetRPS float64, targetLatency, sloLatency time.Duration, breachWindows int) internal.AppOption {
+	return internal.OptionFunc(func(opts *internal.Options) {
+		opts.EnableLoadLimit = true
+		opts.TargetRPS = targetRPS
+		opts.TargetLatency = targetLatency
+		opts.SLOLatency = sloLatency
+		opts.SLOBreachWindows = breachWindows
+	})
+}
@@ -0,0 +1,18 @@
+package codec
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stacktasec/circle/kit/app/internal"
+)
+
+type cborCodec struct{}
+
+// CBOR suits IoT clients that already speak CBOR and would otherwise pay
+// the cost of decoding JSON on constrained hardware.
+func CBOR() internal.Codec { return cborCodec{} }
+
+func (cborCodec) Marshal(v any) ([]byte, error) { return cbor.Marshal(v) }
+
+func (cborCodec) Unmarshal(data []byte, v any) error { return cbor.Unmarshal(data, v) }
+
+func (cborCodec) ContentType() string { return "application/cbor" }
@@ -0,0 +1,18 @@
+package codec
+
+import (
+	"encoding/json"
+	"github.com/stacktasec/circle/kit/app/internal"
+)
+
+type jsonCodec struct{}
+
+// JSON is the default codec used when a request carries no recognized
+// Content-Type/Accept header.
+func JSON() internal.Codec { return jsonCodec{} }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+func (jsonCodec) ContentType() string { return "application/json" }
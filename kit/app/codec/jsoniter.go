@@ -0,0 +1,22 @@
+package codec
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stacktasec/circle/kit/app/internal"
+)
+
+var jsoniterAPI = jsoniter.ConfigCompatibleWithStandardLibrary
+
+type jsoniterCodec struct{}
+
+// JSONIter is a drop-in replacement for JSON using json-iterator/go,
+// another high-throughput alternative to SonicJSON; like SonicJSON it
+// negotiates under application/json, so registering it via WithCodec
+// overrides the default JSON codec rather than adding a second one beside it.
+func JSONIter() internal.Codec { return jsoniterCodec{} }
+
+func (jsoniterCodec) Marshal(v any) ([]byte, error) { return jsoniterAPI.Marshal(v) }
+
+func (jsoniterCodec) Unmarshal(data []byte, v any) error { return jsoniterAPI.Unmarshal(data, v) }
+
+func (jsoniterCodec) ContentType() string { return "application/json" }
@@ -0,0 +1,18 @@
+package codec
+
+import (
+	"github.com/stacktasec/circle/kit/app/internal"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type msgpackCodec struct{}
+
+// MessagePack gives binary clients a compact wire format without changing
+// the bind structs service methods already use.
+func MessagePack() internal.Codec { return msgpackCodec{} }
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) { return msgpack.Marshal(v) }
+
+func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+
+func (msgpackCodec) ContentType() string { return "application/msgpack" }
@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"fmt"
+	"github.com/stacktasec/circle/kit/app/internal"
+	"google.golang.org/protobuf/proto"
+)
+
+type protobufCodec struct{}
+
+// Protobuf only works for request/response structs generated from a .proto
+// file (i.e. ones implementing proto.Message); plain json-tagged bind
+// structs are rejected with a clear error instead of silently falling back.
+func Protobuf() internal.Codec { return protobufCodec{} }
+
+func (protobufCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf codec requires a proto.Message, got %T", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf codec requires a proto.Message, got %T", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
@@ -0,0 +1,20 @@
+package codec
+
+import (
+	"github.com/bytedance/sonic"
+	"github.com/stacktasec/circle/kit/app/internal"
+)
+
+type sonicCodec struct{}
+
+// SonicJSON is a drop-in replacement for JSON using bytedance/sonic's
+// faster encoder/decoder; it negotiates under the same application/json
+// Content-Type, so registering it via WithCodec overrides the default JSON
+// codec rather than adding a second one beside it.
+func SonicJSON() internal.Codec { return sonicCodec{} }
+
+func (sonicCodec) Marshal(v any) ([]byte, error) { return sonic.Marshal(v) }
+
+func (sonicCodec) Unmarshal(data []byte, v any) error { return sonic.Unmarshal(data, v) }
+
+func (sonicCodec) ContentType() string { return "application/json" }
@@ -1,22 +1,37 @@
 package gin
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/iancoleman/strcase"
-	"github.com/juju/ratelimit"
+	"github.com/lucas-clemente/quic-go/http3"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stacktasec/circle/kit/app/codec"
 	"github.com/stacktasec/circle/kit/app/internal"
+	"github.com/stacktasec/circle/kit/app/jwtauth"
 	"github.com/stacktasec/circle/kit/klog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/dig"
+	"google.golang.org/protobuf/proto"
+	"io"
 	"io/fs"
+	"mime"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -24,9 +39,31 @@ import (
 
 const keyRequestID = "X-Request-ID"
 
+// statusClientClosedRequest mirrors nginx's 499: the caller disconnected
+// before the handler finished, distinct from a server-side deadline.
+const statusClientClosedRequest = 499
+
+// clientIDHeader identifies the caller for per-identity rate limiting.
+// Callers that omit it fall back to a single shared "anonymous" bucket.
+const clientIDHeader = "X-Client-ID"
+
+// defaultLimiterKeys bounds how many distinct (route, client) buckets a
+// Limiter keeps before evicting the least-recently-used one.
+const defaultLimiterKeys = 10000
+
 const (
-	respTypeJson   = "json"
+	respTypeJson = "json"
+	// respTypeStream marks a method returning an fs.File, served via DataFromReader.
 	respTypeStream = "stream"
+	// respTypeWriter marks a method that streams its response directly to
+	// the gin writer, e.g. chunked downloads or SSE progress endpoints.
+	respTypeWriter = "writer"
+	// respTypeChan marks a method returning <-chan T: each value is encoded
+	// and flushed as it arrives, as NDJSON or SSE depending on Accept.
+	respTypeChan = "chan"
+	// respTypeReader marks a method returning io.Reader: the body is sniffed
+	// for Content-Type and copied to the client as it's read.
+	respTypeReader = "reader"
 )
 
 type App struct {
@@ -34,8 +71,14 @@ type App struct {
 	options       internal.Options
 	versionGroups map[int]*internal.VersionGroup
 	engine        *gin.Engine
-	limitBucket   *ratelimit.Bucket
+	limiter       internal.Limiter
 	loadValue     atomic.Value
+	shedder       *shedder
+
+	httpServer  *http.Server
+	http3Server *http3.Server
+
+	metrics *appMetrics
 }
 
 func NewApp(opts ...internal.AppOption) *App {
@@ -76,17 +119,30 @@ func (a *App) Provide(constructors ...any) {
 func (a *App) Run() {
 	a.build()
 
-	if a.options.EnableOverloadBreak {
+	if a.options.EnableLoadLimit {
 		a.watch()
 	}
 
-	httpServer := http.Server{
+	httpServer := &http.Server{
 		Addr:           a.options.Addr,
 		Handler:        a.engine,
 		ReadTimeout:    time.Second * 10,
 		WriteTimeout:   time.Second * 10,
 		MaxHeaderBytes: 1 << 16,
 	}
+	a.httpServer = httpServer
+
+	if a.options.EnableQUIC {
+		http3Server := &http3.Server{Server: httpServer}
+		a.http3Server = http3Server
+
+		go func() {
+			klog.Info("http3 server is listening on %s", a.options.Addr)
+			if err := http3Server.ListenAndServeTLS(a.options.Cert, a.options.Key); err != nil {
+				klog.Error("http3 server stopped: %s", err)
+			}
+		}()
+	}
 
 	if a.options.EnableTLS {
 		klog.Info("https server is listening on %s", a.options.Addr)
@@ -101,11 +157,27 @@ func (a *App) Run() {
 	}
 }
 
+// Shutdown closes the HTTP/3 listener when QUIC is enabled, without
+// draining it, then gracefully drains the HTTP listener.
+func (a *App) Shutdown(ctx context.Context) error {
+	if a.http3Server != nil {
+		if err := a.http3Server.Close(); err != nil {
+			return err
+		}
+	}
+
+	if a.httpServer != nil {
+		return a.httpServer.Shutdown(ctx)
+	}
+
+	return nil
+}
+
 func (a *App) build() {
 
 	r := gin.Default()
 
-	if a.options.EnableOverloadBreak {
+	if a.options.EnableLoadLimit {
 		r.Use(func(c *gin.Context) {
 			value := a.loadValue.Load()
 			if value == true {
@@ -114,20 +186,52 @@ func (a *App) build() {
 			}
 			c.Next()
 		})
+
+		if a.options.SLOLatency > 0 {
+			a.shedder = newShedder()
+			r.Use(func(c *gin.Context) {
+				if !a.shedder.admit() {
+					retryAfter := a.shedder.retryAfter(a.shedder.probability())
+					c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter.Seconds()))
+					c.AbortWithStatus(http.StatusServiceUnavailable)
+					return
+				}
+				c.Next()
+			})
+
+			r.GET("/debug/shed", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{
+					"shed_probability":   a.shedder.probability(),
+					"target_concurrency": a.shedder.targetConcurrency(&a.options),
+				})
+			})
+		}
 	}
 
-	if a.options.EnableRateLimit {
-		a.limitBucket = ratelimit.NewBucketWithQuantum(a.options.FillInterval, a.options.Capacity, a.options.Quantum)
+	if a.options.H3AltSvcPort > 0 {
 		r.Use(func(c *gin.Context) {
-			count := a.limitBucket.TakeAvailable(1)
-			if count == 0 {
-				c.AbortWithStatus(http.StatusTooManyRequests)
-				return
-			}
+			c.Header("Alt-Svc", fmt.Sprintf(`h3=":%d"`, a.options.H3AltSvcPort))
 			c.Next()
 		})
 	}
 
+	if a.options.EnableRateLimit {
+		a.limiter = internal.NewBucketLimiter(a.options.FillInterval, a.options.Capacity, a.options.Quantum, defaultLimiterKeys)
+	}
+
+	if a.options.EnableMetrics {
+		a.metrics = newAppMetrics(a.options.MetricsNamespace)
+	}
+
+	if a.options.EnableTracing && a.options.TracerProvider == nil && a.options.OTLPEndpoint != "" {
+		tp, err := newOTLPTracerProvider(a.options.ServiceName, a.options.OTLPEndpoint)
+		if err != nil {
+			klog.Error("otlp tracer provider init failed: %s", err)
+		} else {
+			a.options.TracerProvider = tp
+		}
+	}
+
 	r.NoRoute(func(c *gin.Context) {
 		c.AbortWithStatus(http.StatusNotImplemented)
 	})
@@ -136,6 +240,8 @@ func (a *App) build() {
 
 	a.discovery(r)
 
+	a.mountOpenAPI(r)
+
 	for _, g := range a.versionGroups {
 		a.fillGroups(r.Group(a.options.BaseURL), g)
 	}
@@ -150,6 +256,12 @@ func (a *App) discovery(r *gin.Engine) {
 		welcomeMsg := "Welcome"
 		c.String(http.StatusOK, welcomeMsg)
 	})
+
+	if a.options.EnableMetrics {
+		r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
+	r.Any("/debug/log/level", gin.WrapH(klog.LevelHandler()))
 }
 
 func (a *App) watch() {
@@ -191,6 +303,12 @@ func (a *App) watch() {
 				a.loadValue.Store(true)
 				continue
 			}
+
+			a.loadValue.Store(false)
+
+			if a.shedder != nil {
+				a.shedder.tick(&a.options)
+			}
 		}
 	}()
 }
@@ -206,6 +324,31 @@ type reflectAction struct {
 	methodValue reflect.Value
 	// 请求 返回类型
 	respType string
+	// isUpload marks methods shaped (ctx, *Req, io.Reader) (*Resp, error);
+	// the raw request body is streamed to the method unparsed.
+	isUpload bool
+	// limiter overrides the app-wide one when the owning service implements
+	// RateLimitedAttribute; nil means use the app-wide limiter.
+	limiter internal.Limiter
+	// versionChannel is "stable", "beta", or "alpha", set by fillGroups; used
+	// to label metrics and traces by release channel.
+	versionChannel string
+	// requiredScopes are the scopes a caller's JWT must carry, declared by
+	// the owning service via internal.ScopedAttribute; empty means none.
+	requiredScopes []string
+	// anonymous marks a service that implements internal.AnonymousAttribute
+	// and opted out of handleInterceptors entirely.
+	anonymous bool
+	// allowedCodecs restricts negotiation to these Content-Types when the
+	// owning service implements internal.CodecsAttribute; empty means any
+	// registered Codec is negotiable.
+	allowedCodecs []string
+	// timeout overrides a.options.CtxTimeout when the owning service
+	// implements internal.TimeoutAttribute; zero means use the app default.
+	timeout time.Duration
+	// omitted marks a service that implements internal.OmittedAttribute and
+	// opted out of appearing in the generated OpenAPI document.
+	omitted bool
 }
 
 func (a *App) makeActions(constructor any) []reflectAction {
@@ -238,6 +381,43 @@ func (a *App) makeActions(constructor any) []reflectAction {
 	pointerValue := reflect.ValueOf(rtn)
 	pointerType := pointerValue.Type()
 
+	var svcLimiter internal.Limiter
+	rateLimitedType := reflect.TypeOf((*internal.RateLimitedAttribute)(nil)).Elem()
+	if pointerType.Implements(rateLimitedType) {
+		fillInterval, capacity, quantum := pointerValue.Interface().(internal.RateLimitedAttribute).RateLimit()
+		svcLimiter = internal.NewBucketLimiter(fillInterval, capacity, quantum, defaultLimiterKeys)
+	}
+
+	var scoped internal.ScopedAttribute
+	scopedType := reflect.TypeOf((*internal.ScopedAttribute)(nil)).Elem()
+	if pointerType.Implements(scopedType) {
+		scoped = pointerValue.Interface().(internal.ScopedAttribute)
+	}
+
+	var anonymous bool
+	anonymousType := reflect.TypeOf((*internal.AnonymousAttribute)(nil)).Elem()
+	if pointerType.Implements(anonymousType) {
+		anonymous = pointerValue.Interface().(internal.AnonymousAttribute).Anonymous()
+	}
+
+	var allowedCodecs []string
+	codecsType := reflect.TypeOf((*internal.CodecsAttribute)(nil)).Elem()
+	if pointerType.Implements(codecsType) {
+		allowedCodecs = pointerValue.Interface().(internal.CodecsAttribute).Codecs()
+	}
+
+	var timeout time.Duration
+	timeoutType := reflect.TypeOf((*internal.TimeoutAttribute)(nil)).Elem()
+	if pointerType.Implements(timeoutType) {
+		timeout = pointerValue.Interface().(internal.TimeoutAttribute).Timeout()
+	}
+
+	var omitted bool
+	omittedType := reflect.TypeOf((*internal.OmittedAttribute)(nil)).Elem()
+	if pointerType.Implements(omittedType) {
+		omitted = pointerValue.Interface().(internal.OmittedAttribute).Omitted()
+	}
+
 	var actions []reflectAction
 	for i := 0; i < pointerType.NumMethod(); i++ {
 		// 获得方法
@@ -254,38 +434,154 @@ func (a *App) makeActions(constructor any) []reflectAction {
 		// 检查参数是否符合规定格式
 		inParams := methodType.NumIn()
 		outParams := methodType.NumOut()
-		if inParams != 3 || outParams != 2 {
-			continue
-		}
 
-		// 必须满足 如下 四元组
-		in1 := methodType.In(1)
-		in2 := methodType.In(2)
-		out0 := methodType.Out(0)
-		out1 := methodType.Out(1)
+		svcName, methodName := a.makeName(pointerType.Elem().Name(), method.Name)
 
-		if !satisfyContext(in1) {
-			continue
+		var requiredScopes []string
+		if scoped != nil {
+			requiredScopes = scoped.RequireScope(method.Name)
 		}
 
-		if !satisfyRequest(in2) {
-			continue
-		}
+		switch {
+		case inParams == 3 && outParams == 2 && isChan(methodType.Out(0)):
+			// func(ctx, *Req) (<-chan T, error): server-streaming, rendered
+			// as NDJSON or SSE depending on the caller's Accept header.
+			in1 := methodType.In(1)
+			in2 := methodType.In(2)
+			out1 := methodType.Out(1)
+
+			if !satisfyContext(in1) || !satisfyRequest(in2) {
+				continue
+			}
 
-		respType := mustResponse(out0)
+			mustError(out1)
+
+			actions = append(actions, reflectAction{
+				serviceName:    svcName,
+				methodName:     methodName,
+				bindData:       reflect.New(in2).Interface(),
+				methodValue:    pointerValue.Method(i),
+				respType:       respTypeChan,
+				limiter:        svcLimiter,
+				requiredScopes: requiredScopes,
+				anonymous:      anonymous,
+				allowedCodecs:  allowedCodecs,
+				timeout:        timeout,
+				omitted:        omitted,
+			})
+
+		case inParams == 3 && outParams == 2 && isReader(methodType.Out(0)):
+			// func(ctx, *Req) (io.Reader, error): arbitrary streamed body.
+			in1 := methodType.In(1)
+			in2 := methodType.In(2)
+			out1 := methodType.Out(1)
+
+			if !satisfyContext(in1) || !satisfyRequest(in2) {
+				continue
+			}
 
-		mustError(out1)
+			mustError(out1)
+
+			actions = append(actions, reflectAction{
+				serviceName:    svcName,
+				methodName:     methodName,
+				bindData:       reflect.New(in2).Interface(),
+				methodValue:    pointerValue.Method(i),
+				respType:       respTypeReader,
+				limiter:        svcLimiter,
+				requiredScopes: requiredScopes,
+				anonymous:      anonymous,
+				allowedCodecs:  allowedCodecs,
+				timeout:        timeout,
+				omitted:        omitted,
+			})
+
+		case inParams == 3 && outParams == 2:
+			// func(ctx, *Req) (*Resp, error)
+			in1 := methodType.In(1)
+			in2 := methodType.In(2)
+			out0 := methodType.Out(0)
+			out1 := methodType.Out(1)
+
+			if !satisfyContext(in1) || !satisfyRequest(in2) {
+				continue
+			}
 
-		svcName, methodName := a.makeName(pointerType.Elem().Name(), method.Name)
-		action := reflectAction{
-			serviceName: svcName,
-			methodName:  methodName,
-			bindData:    reflect.New(in2).Interface(),
-			methodValue: pointerValue.Method(i),
-			respType:    respType,
-		}
+			respType := mustResponse(out0)
+			mustError(out1)
+
+			actions = append(actions, reflectAction{
+				serviceName:    svcName,
+				methodName:     methodName,
+				bindData:       reflect.New(in2).Interface(),
+				methodValue:    pointerValue.Method(i),
+				respType:       respType,
+				limiter:        svcLimiter,
+				requiredScopes: requiredScopes,
+				anonymous:      anonymous,
+				allowedCodecs:  allowedCodecs,
+				timeout:        timeout,
+				omitted:        omitted,
+			})
+
+		case inParams == 4 && outParams == 2 && satisfyReader(methodType.In(3)):
+			// func(ctx, *Req, io.Reader) (*Resp, error): streaming upload
+			in1 := methodType.In(1)
+			in2 := methodType.In(2)
+			out0 := methodType.Out(0)
+			out1 := methodType.Out(1)
+
+			if !satisfyContext(in1) || !satisfyRequest(in2) {
+				continue
+			}
+
+			respType := mustResponse(out0)
+			mustError(out1)
+
+			actions = append(actions, reflectAction{
+				serviceName:    svcName,
+				methodName:     methodName,
+				bindData:       reflect.New(in2).Interface(),
+				methodValue:    pointerValue.Method(i),
+				respType:       respType,
+				isUpload:       true,
+				limiter:        svcLimiter,
+				requiredScopes: requiredScopes,
+				anonymous:      anonymous,
+				allowedCodecs:  allowedCodecs,
+				timeout:        timeout,
+				omitted:        omitted,
+			})
+
+		case inParams == 4 && outParams == 1 && satisfyWriter(methodType.In(3)):
+			// func(ctx, *Req, io.Writer) error: streaming (chunked/SSE) download
+			in1 := methodType.In(1)
+			in2 := methodType.In(2)
+			out0 := methodType.Out(0)
+
+			if !satisfyContext(in1) || !satisfyRequest(in2) {
+				continue
+			}
 
-		actions = append(actions, action)
+			mustError(out0)
+
+			actions = append(actions, reflectAction{
+				serviceName:    svcName,
+				methodName:     methodName,
+				bindData:       reflect.New(in2).Interface(),
+				methodValue:    pointerValue.Method(i),
+				respType:       respTypeWriter,
+				limiter:        svcLimiter,
+				requiredScopes: requiredScopes,
+				anonymous:      anonymous,
+				allowedCodecs:  allowedCodecs,
+				timeout:        timeout,
+				omitted:        omitted,
+			})
+
+		default:
+			continue
+		}
 	}
 
 	return actions
@@ -305,38 +601,135 @@ func (a *App) makeName(resource, action string) (string, string) {
 }
 
 func (a *App) fillGroups(routerGroup *gin.RouterGroup, vg *internal.VersionGroup) {
+	a.fillChannel(routerGroup, fmt.Sprintf("/v%d", vg.MainVersion), vg.StableConstructors, "stable")
+	a.fillChannel(routerGroup, fmt.Sprintf("/v%dbeta", vg.MainVersion), vg.BetaConstructors, "beta")
+	a.fillChannel(routerGroup, fmt.Sprintf("/v%dalpha", vg.MainVersion), vg.AlphaConstructors, "alpha")
+}
 
-	for _, constructor := range vg.StableConstructors {
-		g := routerGroup.Group(fmt.Sprintf("/v%d", vg.MainVersion))
-		a.fillActions(g, constructor)
+// fillChannel registers every constructor's actions under path and, if any
+// of them return a <-chan, mounts a single multiplexed WebSocket RPC
+// endpoint at path+"/ws" dispatching across those chan-returning actions by
+// "service.method" - one socket per channel rather than one per action.
+// Actions of other shapes are served over POST only.
+func (a *App) fillChannel(routerGroup *gin.RouterGroup, path string, constructors []any, channel string) {
+	g := routerGroup.Group(path)
+
+	var allActions []reflectAction
+	for _, constructor := range constructors {
+		allActions = append(allActions, a.fillActions(g, constructor, channel)...)
 	}
 
-	for _, constructor := range vg.BetaConstructors {
-		g := routerGroup.Group(fmt.Sprintf("/v%dbeta", vg.MainVersion))
-		a.fillActions(g, constructor)
+	var streamActions []reflectAction
+	for _, action := range allActions {
+		if action.respType == respTypeChan {
+			streamActions = append(streamActions, action)
+		}
 	}
 
-	for _, constructor := range vg.AlphaConstructors {
-		g := routerGroup.Group(fmt.Sprintf("/v%dalpha", vg.MainVersion))
-		a.fillActions(g, constructor)
+	if len(streamActions) > 0 {
+		a.mountWSRPC(g, streamActions)
 	}
 }
 
-func (a *App) fillActions(g *gin.RouterGroup, constructor any) {
+func (a *App) fillActions(g *gin.RouterGroup, constructor any, channel string) []reflectAction {
 
 	actions := a.makeActions(constructor)
+	for i := range actions {
+		actions[i].versionChannel = channel
+	}
 
 	for _, action := range actions {
 
 		g.POST(fmt.Sprintf("/%s/%s", action.serviceName, action.methodName), func(c *gin.Context) {
-			if ok := a.handleInterceptors(c); !ok {
-				return
+			jwtauth.StripClaimsHeader(c.Request.Header)
+
+			if a.metrics != nil {
+				start := time.Now()
+				a.metrics.inFlight.WithLabelValues(action.serviceName, action.methodName, action.versionChannel).Inc()
+				defer func() {
+					status := strconv.Itoa(c.Writer.Status())
+					a.metrics.inFlight.WithLabelValues(action.serviceName, action.methodName, action.versionChannel).Dec()
+					a.metrics.requests.WithLabelValues(action.serviceName, action.methodName, action.versionChannel, status).Inc()
+					a.metrics.duration.WithLabelValues(action.serviceName, action.methodName, action.versionChannel, status).Observe(time.Since(start).Seconds())
+					a.metrics.bytesIn.WithLabelValues(action.serviceName, action.methodName, action.versionChannel, status).Add(float64(c.Request.ContentLength))
+					a.metrics.bytesOut.WithLabelValues(action.serviceName, action.methodName, action.versionChannel, status).Add(float64(c.Writer.Size()))
+				}()
+			}
+
+			if a.shedder != nil {
+				start := time.Now()
+				defer func() {
+					a.shedder.record(action.serviceName+"."+action.methodName, time.Since(start))
+				}()
+			}
+
+			if !action.anonymous {
+				if ok := a.handleInterceptors(c); !ok {
+					return
+				}
 			}
 
+			claims, hasClaims := jwtauth.ClaimsFromHeader(c.Request.Header)
+
+			if len(action.requiredScopes) > 0 {
+				if !hasClaims {
+					c.AbortWithStatus(http.StatusUnauthorized)
+					return
+				}
+
+				for _, scope := range action.requiredScopes {
+					if !claims.HasScope(scope) {
+						c.AbortWithStatus(http.StatusForbidden)
+						return
+					}
+				}
+			}
+
+			if limiter := action.limiter; limiter != nil || a.limiter != nil {
+				if limiter == nil {
+					limiter = a.limiter
+				}
+
+				route := fmt.Sprintf("/%s/%s", action.serviceName, action.methodName)
+				key := rateLimitKey(route, c.Request.Header)
+				if !limiter.Allow(key) {
+					retryAfter := limiter.RetryAfter(key)
+					c.Header("Retry-After", fmt.Sprintf("%.0f", retryAfter.Seconds()))
+					c.AbortWithStatus(http.StatusTooManyRequests)
+					return
+				}
+			}
+
+			reqCodec := a.codecFor(c.Request.Header, action.allowedCodecs)
+
 			req := action.bindData
-			if err := c.ShouldBind(&req); err != nil {
-				c.AbortWithStatus(http.StatusBadRequest)
-				return
+			if action.isUpload {
+				// the body is the upload stream itself; bind metadata from the query string instead.
+				if err := c.ShouldBindQuery(req); err != nil {
+					c.AbortWithStatus(http.StatusBadRequest)
+					return
+				}
+			} else {
+				if a.options.MaxBodyBytes > 0 {
+					c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, a.options.MaxBodyBytes)
+				}
+
+				body, err := io.ReadAll(c.Request.Body)
+				if err != nil {
+					var tooLarge *http.MaxBytesError
+					if errors.As(err, &tooLarge) {
+						c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+						return
+					}
+					c.AbortWithStatus(http.StatusBadRequest)
+					return
+				}
+				if len(body) > 0 {
+					if err := reqCodec.Unmarshal(body, req); err != nil {
+						c.AbortWithStatus(http.StatusBadRequest)
+						return
+					}
+				}
 			}
 
 			i := req.(internal.Request)
@@ -345,23 +738,71 @@ func (a *App) fillActions(g *gin.RouterGroup, constructor any) {
 				return
 			}
 
-			ctx := context.Background()
+			ctx := c.Request.Context()
 
 			reqID := uuid.NewString()
-			ctx = context.WithValue(ctx, keyRequestID, reqID)
-			timeoutCtx, cancel := context.WithTimeout(ctx, a.options.CtxTimeout)
+			ctx = internal.ContextWithRequestID(ctx, reqID)
+			if hasClaims {
+				ctx = internal.ContextWithClaims(ctx, claims)
+			}
+			ctx = klog.ContextWithFields(ctx,
+				klog.RequestID(reqID),
+				klog.String("service", action.serviceName),
+				klog.String("method", action.methodName),
+			)
+
+			var span trace.Span
+			if a.options.TracerProvider != nil {
+				ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(c.Request.Header))
+				ctx, span = a.options.TracerProvider.Tracer("circle").Start(ctx, action.serviceName+"."+action.methodName)
+				span.SetAttributes(attribute.String("request_id", reqID))
+				defer span.End()
+			}
+
+			timeout := a.options.CtxTimeout
+			if action.timeout > 0 {
+				timeout = action.timeout
+			}
+
+			timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 			defer cancel()
 
 			c.Writer.Header().Set(keyRequestID, reqID)
 
 			ctxValue := reflect.ValueOf(timeoutCtx)
 			reqValue := reflect.ValueOf(req).Elem()
-			rtnList := action.methodValue.Call([]reflect.Value{ctxValue, reqValue})
+
+			if action.respType == respTypeWriter {
+				if mimeType(c.Request.Header.Get("Accept")) == "text/event-stream" {
+					c.Writer.Header().Set("Content-Type", "text/event-stream")
+				} else {
+					c.Writer.Header().Set("Content-Type", "application/octet-stream")
+				}
+				c.Writer.WriteHeader(http.StatusOK)
+
+				rtnList := action.methodValue.Call([]reflect.Value{ctxValue, reqValue, reflect.ValueOf(c.Writer)})
+				if errValue := rtnList[0].Interface(); errValue != nil {
+					klog.Error("streaming handler failed: %s", errValue)
+				}
+				c.Writer.Flush()
+				return
+			}
+
+			callArgs := []reflect.Value{ctxValue, reqValue}
+			if action.isUpload {
+				callArgs = append(callArgs, reflect.ValueOf(c.Request.Body))
+			}
+			rtnList := action.methodValue.Call(callArgs)
 
 			// 判断第二个值 是自定义错误
 			// 还是原生error
 			errValue := rtnList[1].Interface()
 			if errValue != nil {
+				if errValue == context.Canceled || timeoutCtx.Err() == context.Canceled {
+					c.AbortWithStatus(statusClientClosedRequest)
+					return
+				}
+
 				if errValue == context.DeadlineExceeded {
 					c.AbortWithStatus(http.StatusGatewayTimeout)
 					return
@@ -369,7 +810,18 @@ func (a *App) fillActions(g *gin.RouterGroup, constructor any) {
 
 				err, ok := errValue.(internal.KnownError)
 				if ok {
-					c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err})
+					err = err.WithRequestID(reqID)
+
+					if span != nil {
+						span.SetStatus(otelcodes.Error, err.Error())
+					}
+
+					data, contentType, encErr := marshalResponse(reqCodec, err)
+					if encErr != nil {
+						c.AbortWithStatus(http.StatusInternalServerError)
+						return
+					}
+					c.Data(err.Code.HTTPStatus(), contentType, data)
 					return
 				} else {
 					c.AbortWithStatus(http.StatusInternalServerError)
@@ -377,6 +829,11 @@ func (a *App) fillActions(g *gin.RouterGroup, constructor any) {
 				}
 			}
 
+			if action.respType == respTypeChan {
+				streamChan(c, reqCodec, rtnList[0])
+				return
+			}
+
 			result := rtnList[0].Interface()
 			if action.respType == respTypeStream {
 				file := result.(fs.File)
@@ -386,17 +843,171 @@ func (a *App) fillActions(g *gin.RouterGroup, constructor any) {
 					return
 				}
 
+				if seeker, ok := file.(io.ReadSeeker); ok {
+					http.ServeContent(c.Writer, c.Request, stat.Name(), stat.ModTime(), seeker)
+					return
+				}
+
 				c.DataFromReader(http.StatusOK, stat.Size(), "application/octet-stream", file, nil)
 				return
 			}
 
+			if action.respType == respTypeReader {
+				streamReader(c, result.(io.Reader))
+				return
+			}
+
 			if result == nil {
 				c.Status(http.StatusNotFound)
 				return
 			}
-			c.JSON(http.StatusOK, gin.H{"result": result})
+
+			data, err := marshalResult(reqCodec, result)
+			if err != nil {
+				c.AbortWithStatus(http.StatusInternalServerError)
+				return
+			}
+			c.Data(http.StatusOK, reqCodec.ContentType(), data)
 		})
 	}
+
+	return actions
+}
+
+// streamChan drains ch, encoding each value with reqCodec and flushing it to
+// c immediately: as text/event-stream "data:" frames if the caller's Accept
+// header asks for SSE, otherwise as newline-delimited JSON.
+func streamChan(c *gin.Context, reqCodec internal.Codec, ch reflect.Value) {
+	sse := mimeType(c.Request.Header.Get("Accept")) == "text/event-stream"
+	if sse {
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+	} else {
+		c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+
+	for {
+		v, ok := ch.Recv()
+		if !ok {
+			return
+		}
+
+		data, err := reqCodec.Marshal(v.Interface())
+		if err != nil {
+			klog.Error("stream encode failed: %s", err)
+			return
+		}
+
+		if sse {
+			c.Writer.Write([]byte("data: "))
+		}
+		c.Writer.Write(data)
+		c.Writer.Write([]byte("\n"))
+		if sse {
+			c.Writer.Write([]byte("\n"))
+		}
+		c.Writer.Flush()
+	}
+}
+
+// streamReader sniffs the first bytes of r for Content-Type, then copies the
+// rest to c as it's read; the server writes in chunks with no Content-Length,
+// so net/http falls back to Transfer-Encoding: chunked.
+func streamReader(c *gin.Context, r io.Reader) {
+	buf := make([]byte, 512)
+	n, _ := io.ReadFull(r, buf)
+
+	c.Writer.Header().Set("Content-Type", http.DetectContentType(buf[:n]))
+	c.Writer.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, io.MultiReader(bytes.NewReader(buf[:n]), r)); err != nil {
+		klog.Error("stream copy failed: %s", err)
+	}
+	c.Writer.Flush()
+}
+
+// codecFor negotiates a Codec from the request's Content-Type, falling back
+// to Accept and finally to JSON so existing clients keep working unchanged.
+// If allowed is non-empty, only those Content-Types are eligible.
+func (a *App) codecFor(h http.Header, allowed []string) internal.Codec {
+	if c, ok := a.options.Codecs[mimeType(h.Get("Content-Type"))]; ok && codecAllowed(c, allowed) {
+		return c
+	}
+
+	if c, ok := a.options.Codecs[mimeType(h.Get("Accept"))]; ok && codecAllowed(c, allowed) {
+		return c
+	}
+
+	// a codec registered under application/json (e.g. SonicJSON) replaces
+	// the built-in one for the fallback too.
+	if c, ok := a.options.Codecs["application/json"]; ok {
+		return c
+	}
+
+	return codec.JSON()
+}
+
+// marshalResult marshals result for reqCodec, skipping the {"result": v}
+// envelope when result itself must be marshaled directly - Protobuf, for
+// instance, requires a concrete proto.Message and rejects anything else,
+// including a map wrapping one.
+func marshalResult(reqCodec internal.Codec, result any) ([]byte, error) {
+	if _, ok := result.(proto.Message); ok {
+		return reqCodec.Marshal(result)
+	}
+	return reqCodec.Marshal(gin.H{"result": result})
+}
+
+// marshalResponse marshals v (an internal.KnownError) for reqCodec, falling
+// back to JSON when the negotiated codec can't represent it - Protobuf has
+// no schema for circle's error envelope, so without a fallback every error
+// response under Protobuf negotiation would be an opaque 500.
+func marshalResponse(reqCodec internal.Codec, v any) (data []byte, contentType string, err error) {
+	data, err = reqCodec.Marshal(v)
+	if err == nil {
+		return data, reqCodec.ContentType(), nil
+	}
+
+	data, err = json.Marshal(v)
+	return data, "application/json", err
+}
+
+func codecAllowed(c internal.Codec, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, contentType := range allowed {
+		if c.ContentType() == contentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rateLimitKey buckets by route and caller identity so one route or client
+// hogging its limit doesn't starve the others.
+func rateLimitKey(route string, h http.Header) string {
+	clientID := h.Get(clientIDHeader)
+	if clientID == "" {
+		clientID = "anonymous"
+	}
+
+	return route + "|" + clientID
+}
+
+func mimeType(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	mt, _, err := mime.ParseMediaType(raw)
+	if err != nil {
+		return raw
+	}
+
+	return mt
 }
 
 func (a *App) handleInterceptors(c *gin.Context) bool {
@@ -410,7 +1021,7 @@ func (a *App) handleInterceptors(c *gin.Context) bool {
 
 		// 隐含：必须有身份 才有权限
 		if a.options.PermInterceptor != nil {
-			if err := a.options.PermInterceptor(h); err != nil {
+			if err := a.options.PermInterceptor(h, c.FullPath()); err != nil {
 				c.AbortWithStatus(http.StatusForbidden)
 				return false
 			}
@@ -0,0 +1,135 @@
+package gin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testAppRequest struct{}
+
+func (testAppRequest) Validate() error { return nil }
+
+type testAppResponse struct{}
+
+// deadlineService overrides TimeoutAttribute with a short deadline and
+// ignores it in the handler itself, so the test can tell whether fillActions
+// actually applies a.options.CtxTimeout/action.timeout to the context it
+// passes in, rather than leaving ctx unbounded.
+type deadlineService struct{}
+
+func (deadlineService) Timeout() time.Duration { return 20 * time.Millisecond }
+
+func (deadlineService) Slow(ctx context.Context, req testAppRequest) (*testAppResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Second):
+		return &testAppResponse{}, nil
+	}
+}
+
+func NewDeadlineService() *deadlineService {
+	return &deadlineService{}
+}
+
+// cancelService blocks until ctx is canceled and closes cancelServiceObserved,
+// letting the test confirm a client disconnect actually reaches the
+// downstream call instead of the handler running against context.Background().
+type cancelService struct{}
+
+var cancelServiceObserved = make(chan struct{})
+
+func (cancelService) Wait(ctx context.Context, req testAppRequest) (*testAppResponse, error) {
+	<-ctx.Done()
+	close(cancelServiceObserved)
+	return nil, ctx.Err()
+}
+
+func NewCancelService() *cancelService {
+	return &cancelService{}
+}
+
+func newTestApp() (*App, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	return NewApp(), gin.New()
+}
+
+func postJSON(t *testing.T, ctx context.Context, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return http.DefaultClient.Do(req)
+}
+
+// TestFillActions_SlowHandlerGetsDeadlineExceeded verifies a service's
+// TimeoutAttribute override actually bounds the context the reflected
+// method receives; fillActions used to build ctx from context.Background()
+// and never apply it, making WithCtxTimeout/TimeoutAttribute a no-op.
+func TestFillActions_SlowHandlerGetsDeadlineExceeded(t *testing.T) {
+	a, r := newTestApp()
+	g := r.Group("")
+	a.fillActions(g, NewDeadlineService, "stable")
+
+	routes := r.Routes()
+	if len(routes) != 1 {
+		t.Fatalf("expected exactly one registered route, got %d", len(routes))
+	}
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := postJSON(t, context.Background(), server.URL+routes[0].Path)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("expected %d Gateway Timeout, got %d", http.StatusGatewayTimeout, resp.StatusCode)
+	}
+}
+
+// TestFillActions_ClientDisconnectCancelsContext verifies that fillActions
+// derives the reflected method's context from c.Request.Context(), so a
+// client that closes its connection mid-flight cancels the downstream call
+// instead of leaving it running against a detached context.Background().
+func TestFillActions_ClientDisconnectCancelsContext(t *testing.T) {
+	a, r := newTestApp()
+	g := r.Group("")
+	a.fillActions(g, NewCancelService, "stable")
+
+	routes := r.Routes()
+	if len(routes) != 1 {
+		t.Fatalf("expected exactly one registered route, got %d", len(routes))
+	}
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := postJSON(t, ctx, server.URL+routes[0].Path)
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	select {
+	case <-cancelServiceObserved:
+	case <-time.After(time.Second):
+		t.Fatal("reflected method never observed ctx.Done() after client disconnect")
+	}
+}
@@ -0,0 +1,53 @@
+package gin
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// appMetrics holds the collectors registered by WithMetrics, labeled by
+// {service, method, version_channel[, status]} so dashboards can slice by
+// any of the reflected dispatch layer's own naming.
+type appMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+	bytesIn  *prometheus.CounterVec
+	bytesOut *prometheus.CounterVec
+}
+
+func newAppMetrics(namespace string) *appMetrics {
+	statusLabels := []string{"service", "method", "version_channel", "status"}
+	inFlightLabels := []string{"service", "method", "version_channel"}
+
+	m := &appMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "total reflected action invocations",
+		}, statusLabels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "reflected action latency",
+		}, statusLabels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "requests_in_flight",
+			Help:      "reflected actions currently executing",
+		}, inFlightLabels),
+		bytesIn: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "request_bytes_total",
+			Help:      "request body bytes read",
+		}, statusLabels),
+		bytesOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "response_bytes_total",
+			Help:      "response body bytes written",
+		}, statusLabels),
+	}
+
+	prometheus.MustRegister(m.requests, m.duration, m.inFlight, m.bytesIn, m.bytesOut)
+
+	return m
+}
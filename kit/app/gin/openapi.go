@@ -0,0 +1,330 @@
+package gin
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stacktasec/circle/kit/app/internal"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+//go:embed assets/swagger.html
+var swaggerUIPage string
+
+// openAPIDoc is a minimal OpenAPI document, just enough to describe the
+// POST-per-action routes fillActions registers.
+type openAPIDoc struct {
+	OpenAPI    string                    `json:"openapi"`
+	Info       openAPIInfo               `json:"info"`
+	Paths      map[string]map[string]any `json:"paths"`
+	Components openAPIComponents         `json:"components"`
+	Tags       []map[string]any          `json:"tags"`
+}
+
+// channelStability maps a version channel to the OpenAPI x-stability value
+// its tag is annotated with; "stable" carries none, since it's the default.
+var channelStability = map[string]string{
+	"beta":  "beta",
+	"alpha": "alpha",
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]any `json:"schemas"`
+	SecuritySchemes map[string]any `json:"securitySchemes"`
+}
+
+var bearerAuthScheme = map[string]any{
+	"type":         "http",
+	"scheme":       "bearer",
+	"bearerFormat": "JWT",
+}
+
+var errorResponses = map[string]any{
+	"400": openAPIResponse("bad request"),
+	"401": openAPIResponse("unauthorized"),
+	"403": openAPIResponse("forbidden"),
+	"409": openAPIResponse("known error"),
+	"429": openAPIResponse("rate limited"),
+	"500": openAPIResponse("internal error"),
+	"504": openAPIResponse("timeout"),
+}
+
+func openAPIResponse(desc string) map[string]any {
+	return map[string]any{"description": desc}
+}
+
+// mountOpenAPI walks versionGroups, reflects over every bind struct/response
+// pair makeActions already validated, and serves the resulting document
+// plus a Swagger UI under baseURL.
+func (a *App) mountOpenAPI(r *gin.Engine) {
+	r.GET(a.options.BaseURL+"/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, a.buildOpenAPI())
+	})
+
+	serveSwaggerUI := func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.String(http.StatusOK, fmt.Sprintf(swaggerUIPage, a.options.BaseURL+"/openapi.json"))
+	}
+
+	r.GET(a.options.BaseURL+"/swagger", serveSwaggerUI)
+	r.GET(a.options.BaseURL+"/docs", serveSwaggerUI)
+}
+
+// WriteOpenAPI encodes the same document served at /openapi.json to w, for
+// callers that want the spec without running the server, e.g. to check it
+// into source control or feed a codegen tool.
+func (a *App) WriteOpenAPI(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a.buildOpenAPI())
+}
+
+func (a *App) buildOpenAPI() openAPIDoc {
+	doc := openAPIDoc{
+		OpenAPI: "3.1.0",
+		Info:    openAPIInfo{Title: "circle", Version: "1"},
+		Paths:   make(map[string]map[string]any),
+		Components: openAPIComponents{
+			Schemas:         make(map[string]any),
+			SecuritySchemes: map[string]any{"bearerAuth": bearerAuthScheme},
+		},
+	}
+
+	schemas := newSchemaCache(doc.Components.Schemas)
+	seenTags := map[string]bool{}
+
+	for _, g := range a.versionGroups {
+		a.describeGroup(&doc, schemas, seenTags, g)
+	}
+
+	return doc
+}
+
+func (a *App) describeGroup(doc *openAPIDoc, schemas *schemaCache, seenTags map[string]bool, vg *internal.VersionGroup) {
+	type channelGroup struct {
+		prefix       string
+		tag          string
+		constructors []any
+	}
+
+	channels := []channelGroup{
+		{fmt.Sprintf("/v%d", vg.MainVersion), fmt.Sprintf("v%d", vg.MainVersion), vg.StableConstructors},
+		{fmt.Sprintf("/v%dbeta", vg.MainVersion), fmt.Sprintf("v%dbeta", vg.MainVersion), vg.BetaConstructors},
+		{fmt.Sprintf("/v%dalpha", vg.MainVersion), fmt.Sprintf("v%dalpha", vg.MainVersion), vg.AlphaConstructors},
+	}
+
+	for _, channel := range channels {
+		if !seenTags[channel.tag] {
+			seenTags[channel.tag] = true
+			doc.Tags = append(doc.Tags, tagFor(channel.tag))
+		}
+
+		for _, constructor := range channel.constructors {
+			for _, action := range a.makeActions(constructor) {
+				if action.omitted {
+					continue
+				}
+
+				path := a.options.BaseURL + channel.prefix + "/" + action.serviceName + "/" + action.methodName
+				reqType := reflect.TypeOf(action.bindData).Elem()
+
+				operation := map[string]any{
+					"operationId": action.serviceName + "." + action.methodName,
+					"tags":        []string{channel.tag},
+					"requestBody": map[string]any{
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": schemas.describe(reqType),
+							},
+						},
+					},
+					"responses": withOKResponse(action, schemas),
+				}
+
+				if docAttr, ok := action.bindData.(internal.DocAttribute); ok {
+					operation["description"] = docAttr.Doc()
+				}
+
+				if action.anonymous {
+					operation["security"] = []any{}
+				} else {
+					operation["security"] = []any{map[string]any{"bearerAuth": []string{}}}
+				}
+
+				doc.Paths[path] = map[string]any{"post": operation}
+			}
+		}
+	}
+}
+
+// tagFor builds the OpenAPI tag object for a version channel, annotated
+// with x-stability when the channel isn't stable.
+func tagFor(tag string) map[string]any {
+	t := map[string]any{"name": tag}
+	if stability, ok := channelStability[channelOf(tag)]; ok {
+		t["x-stability"] = stability
+	}
+	return t
+}
+
+// channelOf recovers "beta"/"alpha"/"stable" from a tag like "v1beta", the
+// same suffix fillGroups appended when building the route prefix.
+func channelOf(tag string) string {
+	if strings.HasSuffix(tag, "beta") {
+		return "beta"
+	}
+	if strings.HasSuffix(tag, "alpha") {
+		return "alpha"
+	}
+	return "stable"
+}
+
+// withOKResponse describes a JSON action's 200 body as the {"result": v}
+// envelope around its reflected response struct; other response shapes
+// (streams, readers, writers, channels) get a bare object schema.
+func withOKResponse(action reflectAction, schemas *schemaCache) map[string]any {
+	okSchema := map[string]any{"type": "object"}
+	if action.respType == respTypeJson {
+		okSchema["properties"] = map[string]any{
+			"result": schemas.describe(action.methodValue.Type().Out(0)),
+		}
+	}
+
+	responses := map[string]any{
+		"200": map[string]any{
+			"description": "ok",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": okSchema,
+				},
+			},
+		},
+	}
+
+	for status, resp := range errorResponses {
+		responses[status] = resp
+	}
+
+	return responses
+}
+
+// schemaCache memoizes the component schema for each struct type it's asked
+// to describe, so a type reachable from two different actions is only
+// written once and a self-referential type (e.g. a tree node with a
+// []*Node child field) resolves to a $ref instead of recursing forever.
+type schemaCache struct {
+	components map[string]any
+	seen       map[reflect.Type]string
+}
+
+func newSchemaCache(components map[string]any) *schemaCache {
+	return &schemaCache{components: components, seen: map[reflect.Type]string{}}
+}
+
+// describe returns a schema reference for t, generating and registering its
+// component schema on first use.
+func (c *schemaCache) describe(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return c.describe(t.Elem())
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		return map[string]any{"type": "array", "items": c.describe(t.Elem())}
+	}
+
+	if t.Kind() != reflect.Struct {
+		return map[string]any{"type": jsonSchemaType(t)}
+	}
+
+	if name, ok := c.seen[t]; ok {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = fmt.Sprintf("anon%d", len(c.seen))
+	}
+	c.seen[t] = name
+
+	// Register the $ref before recursing into fields, so a field whose type
+	// refers back to t resolves to this entry instead of looping forever.
+	c.components[name] = map[string]any{"type": "object"}
+	c.components[name] = c.describeStruct(t)
+
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// describeStruct derives a JSON-Schema object from a bind struct's json/form
+// tags, using doc as the property description when present.
+func (c *schemaCache) describeStruct(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			name, _, _ = strings.Cut(field.Tag.Get("form"), ",")
+		}
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		var schema map[string]any
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer || fieldType.Kind() == reflect.Struct ||
+			((fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) && elemIsStruct(fieldType)) {
+			schema = c.describe(fieldType)
+		} else {
+			schema = map[string]any{"type": jsonSchemaType(fieldType)}
+		}
+
+		if doc := field.Tag.Get("doc"); doc != "" {
+			schema["description"] = doc
+		}
+
+		properties[name] = schema
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+func elemIsStruct(t reflect.Type) bool {
+	elem := t.Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
+}
+
+func jsonSchemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -3,6 +3,7 @@ package gin
 import (
 	"context"
 	"github.com/stacktasec/circle/kit/app/internal"
+	"io"
 	"io/fs"
 	"reflect"
 	"runtime"
@@ -35,6 +36,30 @@ func mustResponse(t reflect.Type) string {
 	return respTypeJson
 }
 
+func satisfyReader(t reflect.Type) bool {
+	readerType := reflect.TypeOf((*io.Reader)(nil)).Elem()
+	return t.AssignableTo(readerType)
+}
+
+func satisfyWriter(t reflect.Type) bool {
+	writerType := reflect.TypeOf((*io.Writer)(nil)).Elem()
+	return t.AssignableTo(writerType)
+}
+
+// isChan reports whether t is a receive-capable channel, the shape of a
+// server-streaming method's first return value: func(ctx, *Req) (<-chan T, error).
+func isChan(t reflect.Type) bool {
+	return t.Kind() == reflect.Chan && t.ChanDir()&reflect.RecvDir != 0
+}
+
+// isReader reports whether t is returned as an io.Reader rather than the
+// usual *Resp, the shape of a method streaming an arbitrary response body:
+// func(ctx, *Req) (io.Reader, error).
+func isReader(t reflect.Type) bool {
+	readerType := reflect.TypeOf((*io.Reader)(nil)).Elem()
+	return t.Implements(readerType)
+}
+
 func mustError(t reflect.Type) {
 	errType := reflect.TypeOf((*error)(nil)).Elem()
 	if !t.Implements(errType) {
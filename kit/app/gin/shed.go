@@ -0,0 +1,125 @@
+package gin
+
+import (
+	"github.com/stacktasec/circle/kit/app/internal"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const shedRingSize = 256
+
+// endpointStats tracks recent latencies for one "service.method", feeding
+// the shedder's worst-endpoint p95 calculation.
+type endpointStats struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (s *endpointStats) observe(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.samples) < shedRingSize {
+		s.samples = append(s.samples, d)
+		return
+	}
+	s.samples[s.next] = d
+	s.next = (s.next + 1) % shedRingSize
+}
+
+func (s *endpointStats) percentile(p float64) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), s.samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// shedder is a latency-driven admission controller layered on top of
+// App.watch's CPU/mem hard ceiling: it tracks per-endpoint latency in a
+// ring buffer, and when the worst endpoint's p95 exceeds
+// Options.SLOLatency for SLOBreachWindows consecutive windows, ramps a
+// rejection probability up; it ramps back down once latency recovers.
+type shedder struct {
+	stats sync.Map // string -> *endpointStats
+
+	breachCount     int32
+	shedProbability atomic.Value // float64
+}
+
+func newShedder() *shedder {
+	s := &shedder{}
+	s.shedProbability.Store(0.0)
+	return s
+}
+
+func (s *shedder) endpoint(key string) *endpointStats {
+	v, _ := s.stats.LoadOrStore(key, &endpointStats{})
+	return v.(*endpointStats)
+}
+
+func (s *shedder) record(key string, d time.Duration) {
+	s.endpoint(key).observe(d)
+}
+
+// tick recomputes the worst per-endpoint p95 and adjusts shedProbability;
+// called once per window from App.watch alongside the CPU/mem check.
+func (s *shedder) tick(opts *internal.Options) {
+	var worstP95 time.Duration
+	s.stats.Range(func(_, v any) bool {
+		if p95 := v.(*endpointStats).percentile(0.95); p95 > worstP95 {
+			worstP95 = p95
+		}
+		return true
+	})
+
+	prob := s.shedProbability.Load().(float64)
+
+	if opts.SLOLatency > 0 && worstP95 > opts.SLOLatency {
+		breach := atomic.AddInt32(&s.breachCount, 1)
+		if breach >= int32(opts.SLOBreachWindows) {
+			s.shedProbability.Store(math.Min(1, prob+0.2))
+		}
+		return
+	}
+
+	atomic.StoreInt32(&s.breachCount, 0)
+	if prob > 0 {
+		s.shedProbability.Store(math.Max(0, prob-0.1))
+	}
+}
+
+func (s *shedder) probability() float64 {
+	return s.shedProbability.Load().(float64)
+}
+
+// targetConcurrency applies Little's law (L = λW) to the configured target
+// throughput and latency. The value is reported at /debug/shed only;
+// admission is decided by the shed probability alone, not by this number.
+func (s *shedder) targetConcurrency(opts *internal.Options) float64 {
+	return opts.TargetRPS * opts.TargetLatency.Seconds()
+}
+
+// retryAfter scales with the current shed probability (1s at 0, 5s at 1),
+// so a shedder that has only just started rejecting asks callers to back
+// off briefly and one rejecting most requests asks for longer.
+func (s *shedder) retryAfter(prob float64) time.Duration {
+	return time.Duration(1+prob*4) * time.Second
+}
+
+// admit rolls the dice against the current shed probability; called from
+// the adaptive-shedding middleware for every request once EnableLoadLimit
+// and a non-zero SLOLatency are configured.
+func (s *shedder) admit() bool {
+	prob := s.probability()
+	return prob == 0 || rand.Float64() >= prob
+}
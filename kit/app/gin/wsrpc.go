@@ -0,0 +1,250 @@
+package gin
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stacktasec/circle/kit/app/internal"
+	"github.com/stacktasec/circle/kit/app/jwtauth"
+	"github.com/stacktasec/circle/kit/klog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var wsRPCUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+const (
+	wsRPCPingInterval = 30 * time.Second
+	wsRPCPongWait     = 60 * time.Second
+)
+
+// wsRPCRequest is one inbound multiplexed call; id correlates it with its
+// response frame, method is "service.method" as registered by makeActions.
+type wsRPCRequest struct {
+	ID      string          `json:"id"`
+	Method  string          `json:"method"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// wsRPCResponse is the outbound frame for a completed call; Error is set
+// instead of Result when the call failed, in the same envelope shape
+// fillActions reports over POST.
+type wsRPCResponse struct {
+	ID     string               `json:"id"`
+	Result any                  `json:"result,omitempty"`
+	Error  *internal.KnownError `json:"error,omitempty"`
+}
+
+// mountWSRPC registers a WebSocket upgrade at g's "/ws" that multiplexes
+// concurrent calls over actions on a single connection: each inbound frame
+// is dispatched by "service.method" to the same reflected handler POST uses,
+// and its result (or error) is written back tagged with the same id.
+func (a *App) mountWSRPC(g *gin.RouterGroup, actions []reflectAction) {
+	byMethod := make(map[string]reflectAction, len(actions))
+	for _, action := range actions {
+		byMethod[action.serviceName+"."+action.methodName] = action
+	}
+
+	g.GET("/ws", func(c *gin.Context) {
+		jwtauth.StripClaimsHeader(c.Request.Header)
+
+		conn, err := wsRPCUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		a.serveWSRPC(c, conn, byMethod)
+	})
+}
+
+func (a *App) serveWSRPC(c *gin.Context, conn *websocket.Conn, byMethod map[string]reflectAction) {
+	var writeMu sync.Mutex
+
+	_ = conn.SetReadDeadline(time.Now().Add(wsRPCPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsRPCPongWait))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(wsRPCPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				writeMu.Lock()
+				err := conn.WriteMessage(websocket.PingMessage, nil)
+				writeMu.Unlock()
+				if err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var req wsRPCRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+
+		if limiter := a.limiter; limiter != nil {
+			key := rateLimitKey(req.Method, c.Request.Header)
+			if !limiter.Allow(key) {
+				err := internal.NewError(internal.CodeResourceExhausted, "rate limited").(internal.KnownError)
+				a.writeWSRPC(conn, &writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+				continue
+			}
+		}
+
+		action, ok := byMethod[req.Method]
+		if !ok {
+			err := internal.NewError(internal.CodeNotFound, "unknown method "+req.Method).(internal.KnownError)
+			a.writeWSRPC(conn, &writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+			continue
+		}
+
+		wg.Add(1)
+		go func(req wsRPCRequest, action reflectAction) {
+			defer wg.Done()
+			a.callWSRPC(c, conn, &writeMu, req, action)
+		}(req, action)
+	}
+}
+
+// callWSRPC decodes req.Payload into action's bind struct, validates it,
+// calls the reflected method with a context deadline derived the same way
+// fillActions derives one for POST, and writes the result back framed with
+// req.ID.
+func (a *App) callWSRPC(c *gin.Context, conn *websocket.Conn, writeMu *sync.Mutex, req wsRPCRequest, action reflectAction) {
+	timeout := a.options.CtxTimeout
+	if action.timeout > 0 {
+		timeout = action.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	reqID := uuid.NewString()
+
+	if authErr := a.authorizeWSCall(c, action); authErr != nil {
+		authErr.RequestID = reqID
+		a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Error: authErr})
+		return
+	}
+
+	reqPtr := reflect.New(reflect.TypeOf(action.bindData).Elem())
+	if len(req.Payload) > 0 {
+		if jsonErr := json.Unmarshal(req.Payload, reqPtr.Interface()); jsonErr != nil {
+			err := internal.NewError(internal.CodeInvalidArgument, jsonErr.Error()).(internal.KnownError).WithRequestID(reqID)
+			a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+			return
+		}
+	}
+
+	i := reqPtr.Interface().(internal.Request)
+	if validateErr := i.Validate(); validateErr != nil {
+		err := internal.NewError(internal.CodeInvalidArgument, validateErr.Error()).(internal.KnownError).WithRequestID(reqID)
+		a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+		return
+	}
+
+	rtnList := action.methodValue.Call([]reflect.Value{reflect.ValueOf(ctx), reqPtr.Elem()})
+
+	// 判断第二个值 是自定义错误
+	// 还是原生error
+	errValue := rtnList[1].Interface()
+	if errValue != nil {
+		if err, ok := errValue.(internal.KnownError); ok {
+			err = err.WithRequestID(reqID)
+			a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+			return
+		}
+		err := internal.NewError(internal.CodeInternal, "internal error").(internal.KnownError).WithRequestID(reqID)
+		a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Error: &err})
+		return
+	}
+
+	if action.respType == respTypeChan {
+		// a channel can't be JSON-encoded as one result; write one frame per
+		// received value, each tagged with req.ID.
+		ch := rtnList[0]
+		for {
+			v, ok := ch.Recv()
+			if !ok {
+				return
+			}
+			a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Result: v.Interface()})
+		}
+	}
+
+	a.writeWSRPC(conn, writeMu, wsRPCResponse{ID: req.ID, Result: rtnList[0].Interface()})
+}
+
+// authorizeWSCall mirrors fillActions' anonymous-skip, IDInterceptor/
+// PermInterceptor, and requiredScopes checks for a single multiplexed call,
+// reporting failure as a KnownError frame instead of aborting c.Writer,
+// since the HTTP response was already consumed by the WebSocket upgrade.
+func (a *App) authorizeWSCall(c *gin.Context, action reflectAction) *internal.KnownError {
+	h := c.Request.Header
+
+	if !action.anonymous && a.options.IDInterceptor != nil {
+		if err := a.options.IDInterceptor(h); err != nil {
+			e := internal.NewError(internal.CodeUnauthenticated, err.Error()).(internal.KnownError)
+			return &e
+		}
+
+		if a.options.PermInterceptor != nil {
+			// the same "/v1/service/method" route fillActions passes via c.FullPath().
+			route := strings.TrimSuffix(c.FullPath(), "/ws") + "/" + action.serviceName + "/" + action.methodName
+			if err := a.options.PermInterceptor(h, route); err != nil {
+				e := internal.NewError(internal.CodePermissionDenied, err.Error()).(internal.KnownError)
+				return &e
+			}
+		}
+	}
+
+	if len(action.requiredScopes) > 0 {
+		claims, hasClaims := jwtauth.ClaimsFromHeader(h)
+		if !hasClaims {
+			e := internal.NewError(internal.CodeUnauthenticated, "missing claims").(internal.KnownError)
+			return &e
+		}
+
+		for _, scope := range action.requiredScopes {
+			if !claims.HasScope(scope) {
+				e := internal.NewError(internal.CodePermissionDenied, "missing scope "+scope).(internal.KnownError)
+				return &e
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *App) writeWSRPC(conn *websocket.Conn, writeMu *sync.Mutex, resp wsRPCResponse) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	if err := conn.WriteJSON(resp); err != nil {
+		klog.Error("ws rpc write failed: %s", err)
+	}
+}
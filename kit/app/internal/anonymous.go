@@ -0,0 +1,8 @@
+package internal
+
+// AnonymousAttribute lets a service opt all of its actions out of the
+// configured IDInterceptor/PermInterceptor pair, analogous to
+// RateLimitedAttribute and ScopedAttribute.
+type AnonymousAttribute interface {
+	Anonymous() bool
+}
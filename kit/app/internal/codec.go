@@ -0,0 +1,11 @@
+package internal
+
+// Codec marshals and unmarshals the request/response payloads the reflection
+// dispatcher moves between the wire and a service method. Built-in
+// implementations live in kit/app/codec; WithCodec registers additional ones
+// keyed by the MIME type returned from ContentType.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	ContentType() string
+}
@@ -0,0 +1,9 @@
+package internal
+
+// CodecsAttribute lets a service restrict which registered Codecs its
+// actions negotiate over, by Content-Type, instead of accepting any codec
+// the app registered via WithCodec. Useful for an action that only a
+// protobuf-speaking backend client calls and that JSON should never reach.
+type CodecsAttribute interface {
+	Codecs() []string
+}
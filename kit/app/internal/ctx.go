@@ -0,0 +1,35 @@
+package internal
+
+import "context"
+
+type ctxKey string
+
+const (
+	requestIDKey ctxKey = "request_id"
+	claimsKey    ctxKey = "claims"
+)
+
+// ContextWithRequestID stashes id on ctx for RequestIDFromContext to read
+// back later in the call chain.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext recovers the request id ContextWithRequestID stashed,
+// if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// ContextWithClaims stashes the authenticated caller's claims on ctx; kept as
+// any so this package doesn't need to depend on jwtauth's Claims type.
+func ContextWithClaims(ctx context.Context, claims any) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext recovers whatever ContextWithClaims stashed, if any.
+func ClaimsFromContext(ctx context.Context) (any, bool) {
+	v := ctx.Value(claimsKey)
+	return v, v != nil
+}
@@ -0,0 +1,8 @@
+package internal
+
+// DocAttribute lets a request struct supply its own OpenAPI operation
+// description instead of (or in addition to) field-level "doc" tags,
+// analogous to RateLimitedAttribute, ScopedAttribute, and friends.
+type DocAttribute interface {
+	Doc() string
+}
@@ -1,14 +1,61 @@
 package internal
 
-import "fmt"
+import (
+	"fmt"
+	"net/http"
+)
 
+// Code is a well-known error category a handler can return, independent of
+// the HTTP status it happens to map to - callers match on Code, not status.
+type Code string
+
+const (
+	CodeInvalidArgument   Code = "INVALID_ARGUMENT"
+	CodeNotFound          Code = "NOT_FOUND"
+	CodeAlreadyExists     Code = "ALREADY_EXISTS"
+	CodePermissionDenied  Code = "PERMISSION_DENIED"
+	CodeUnauthenticated   Code = "UNAUTHENTICATED"
+	CodeResourceExhausted Code = "RESOURCE_EXHAUSTED"
+	CodeDeadlineExceeded  Code = "DEADLINE_EXCEEDED"
+	CodeUnavailable       Code = "UNAVAILABLE"
+	CodeInternal          Code = "INTERNAL"
+)
+
+var httpStatus = map[Code]int{
+	CodeInvalidArgument:   http.StatusBadRequest,
+	CodeNotFound:          http.StatusNotFound,
+	CodeAlreadyExists:     http.StatusConflict,
+	CodePermissionDenied:  http.StatusForbidden,
+	CodeUnauthenticated:   http.StatusUnauthorized,
+	CodeResourceExhausted: http.StatusTooManyRequests,
+	CodeDeadlineExceeded:  http.StatusGatewayTimeout,
+	CodeUnavailable:       http.StatusServiceUnavailable,
+	CodeInternal:          http.StatusInternalServerError,
+}
+
+// HTTPStatus maps c to the status fillActions responds with; unrecognized
+// or empty codes (e.g. from the old two-field MakeKnownError) fall back to
+// 409, matching the scheme this replaces.
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatus[c]; ok {
+		return status
+	}
+
+	return http.StatusConflict
+}
+
+// KnownError is the error shape fillActions recognizes and reports to
+// callers as a JSON envelope instead of a bare 500; everything else a
+// handler returns is treated as internal and hides its message.
 type KnownError struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Code      Code   `json:"err_code"`
+	Message   string `json:"err_msg"`
+	RequestID string `json:"request_id,omitempty"`
+	Details   []any  `json:"details,omitempty"`
 }
 
 func (k KnownError) Error() string {
-	return fmt.Sprintf("[Status] %s [Message] %s", k.Status, k.Message)
+	return fmt.Sprintf("[%s] %s", k.Code, k.Message)
 }
 
 func (k KnownError) Is(err error) bool {
@@ -17,5 +64,19 @@ func (k KnownError) Is(err error) bool {
 		return false
 	}
 
-	return k.Status == nErr.Status && k.Message == nErr.Message
+	return k.Code == nErr.Code && k.Message == nErr.Message
+}
+
+// WithRequestID returns a copy of k carrying id, set by fillActions from the
+// same uuid it stashes in ctx and the X-Request-ID header.
+func (k KnownError) WithRequestID(id string) KnownError {
+	k.RequestID = id
+	return k
+}
+
+// NewError builds a KnownError of code carrying message and any details,
+// which are marshaled verbatim - typically per-field violations or other
+// JSON-serializable structs describing why the call failed.
+func NewError(code Code, message string, details ...any) error {
+	return KnownError{Code: code, Message: message, Details: details}
 }
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/juju/ratelimit"
+	"time"
+)
+
+// Limiter decides whether a request identified by key may proceed. The
+// default implementation below buckets by (route, client id) instead of a
+// single app-wide bucket, so one noisy route or identity can't starve the
+// rest.
+type Limiter interface {
+	Allow(key string) bool
+	// RetryAfter reports how long the caller identified by key should wait
+	// before its bucket next has a token available.
+	RetryAfter(key string) time.Duration
+}
+
+// RateLimitedAttribute lets a service opt its actions into a bucket sized
+// differently from the one passed to WithRateLimit, analogous to how
+// AnonymousAttribute/OmittedAttribute let a service opt out of other
+// cross-cutting behavior (the IDInterceptor/PermInterceptor pair and the
+// generated OpenAPI document, respectively).
+type RateLimitedAttribute interface {
+	RateLimit() (fillInterval time.Duration, capacity, quantum int64)
+}
+
+type bucketLimiter struct {
+	fillInterval time.Duration
+	capacity     int64
+	quantum      int64
+	buckets      *lru.Cache[string, *ratelimit.Bucket]
+}
+
+// NewBucketLimiter builds a per-key token-bucket Limiter. Once more than
+// maxKeys distinct keys have been seen, the least-recently-used one is
+// evicted so memory stays bounded under a churning set of client IDs.
+func NewBucketLimiter(fillInterval time.Duration, capacity, quantum int64, maxKeys int) Limiter {
+	buckets, err := lru.New[string, *ratelimit.Bucket](maxKeys)
+	if err != nil {
+		panic(err)
+	}
+
+	return &bucketLimiter{fillInterval: fillInterval, capacity: capacity, quantum: quantum, buckets: buckets}
+}
+
+func (b *bucketLimiter) bucketFor(key string) *ratelimit.Bucket {
+	if bucket, ok := b.buckets.Get(key); ok {
+		return bucket
+	}
+
+	bucket := ratelimit.NewBucketWithQuantum(b.fillInterval, b.capacity, b.quantum)
+	b.buckets.Add(key, bucket)
+	return bucket
+}
+
+func (b *bucketLimiter) Allow(key string) bool {
+	return b.bucketFor(key).TakeAvailable(1) > 0
+}
+
+func (b *bucketLimiter) RetryAfter(key string) time.Duration {
+	rate := b.bucketFor(key).Rate()
+	if rate <= 0 {
+		return b.fillInterval
+	}
+
+	return time.Duration(float64(time.Second) / rate)
+}
@@ -0,0 +1,9 @@
+package internal
+
+// OmittedAttribute lets a service keep serving its routes while being left
+// out of the OpenAPI document gin.App.buildOpenAPI builds, analogous to how
+// AnonymousAttribute opts a service out of the IDInterceptor/PermInterceptor
+// pair.
+type OmittedAttribute interface {
+	Omitted() bool
+}
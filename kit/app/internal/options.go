@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
 	"time"
 )
@@ -18,17 +19,30 @@ func (opt OptionFunc) Apply(opts *Options) {
 type Options struct {
 	Addr string
 
-	EnableTLS bool
-	Cert      string
-	Key       string
+	EnableTLS  bool
+	EnableQUIC bool
+	Cert       string
+	Key        string
+
+	// H3AltSvcPort advertises a separate QUIC endpoint via Alt-Svc when a
+	// TLS terminator in front of this app serves HTTP/3 itself. Zero disables it.
+	H3AltSvcPort int
 
 	BaseURL    string
 	CtxTimeout time.Duration
 
+	// MaxBodyBytes caps a request body fillActions will decode; zero means
+	// unbounded. Exceeding it aborts with 413 before the codec ever sees
+	// the body, the request-body analogue of MaxHeaderBytes.
+	MaxBodyBytes int64
+
 	Suffixes []string
 
-	IDInterceptor   func(h http.Header) error
-	PermInterceptor func(h http.Header) error
+	IDInterceptor func(h http.Header) error
+	// PermInterceptor runs after IDInterceptor and additionally receives the
+	// resolved route (e.g. "/v1/orders/create"), so it can enforce a
+	// per-route policy such as jwtauth.NewAuthorizer's role->glob map.
+	PermInterceptor func(h http.Header, route string) error
 
 	EnableRateLimit bool
 	FillInterval    time.Duration
@@ -38,6 +52,33 @@ type Options struct {
 	EnableLoadLimit bool
 	MaxCpuPercent   float64
 	MaxMemPercent   float64
+
+	// TargetRPS and TargetLatency give a target concurrency via Little's law
+	// (concurrency = TargetRPS * TargetLatency), reported at /debug/shed and
+	// not used to cap admission; SLOLatency is
+	// the p95 ceiling that, once breached for SLOBreachWindows consecutive
+	// one-minute windows, ramps up a probabilistic 503 shed rate. Zero
+	// SLOLatency disables latency-driven shedding, leaving the CPU/mem
+	// ceiling above as the only admission control.
+	TargetRPS        float64
+	TargetLatency    time.Duration
+	SLOLatency       time.Duration
+	SLOBreachWindows int
+
+	// Codecs holds additional Codec implementations keyed by MIME type,
+	// registered via WithCodec. JSON is always available as the fallback.
+	Codecs map[string]Codec
+
+	EnableMetrics    bool
+	MetricsNamespace string
+
+	EnableTracing  bool
+	TracerProvider trace.TracerProvider
+	// ServiceName and OTLPEndpoint let WithOTLPTracing build its own
+	// TracerProvider instead of requiring the caller to assemble one;
+	// ignored when TracerProvider is already set via WithTracing.
+	ServiceName  string
+	OTLPEndpoint string
 }
 
 func (o *Options) Ensure() {
@@ -52,4 +93,8 @@ func (o *Options) Ensure() {
 	if len(o.Suffixes) == 0 {
 		o.Suffixes = []string{"service", "handler", "usecase", "controller"}
 	}
+
+	if o.SLOLatency > 0 && o.SLOBreachWindows == 0 {
+		o.SLOBreachWindows = 3
+	}
 }
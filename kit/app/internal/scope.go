@@ -0,0 +1,12 @@
+package internal
+
+// ScopedAttribute lets a service declare, per action, which scopes a
+// caller's token must carry, analogous to how RateLimitedAttribute lets a
+// service opt its actions into a differently sized bucket. The dispatcher
+// checks the declared scopes against the Claims a jwtauth Authenticator
+// stashed on the request header.
+type ScopedAttribute interface {
+	// RequireScope returns the scopes an action needs; an empty or nil
+	// result means the action has no scope requirement of its own.
+	RequireScope(method string) []string
+}
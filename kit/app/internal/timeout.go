@@ -0,0 +1,10 @@
+package internal
+
+import "time"
+
+// TimeoutAttribute lets a service override the server-wide default deadline
+// (Options.CtxTimeout) for its own actions, analogous to RateLimitedAttribute,
+// ScopedAttribute, and AnonymousAttribute.
+type TimeoutAttribute interface {
+	Timeout() time.Duration
+}
@@ -0,0 +1,81 @@
+package jwtauth
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingToken is returned when the request has no Authorization
+	// bearer token at all.
+	ErrMissingToken = errors.New("jwtauth: missing bearer token")
+)
+
+// Options configures an Authenticator built by NewAuthenticator.
+type Options struct {
+	// ClockSkew is how much expiry/not-before checks tolerate clock drift
+	// between the issuer and this server.
+	ClockSkew time.Duration
+	// ValidMethods restricts accepted signing algorithms, e.g.
+	// []string{"RS256", "EdDSA"}. Empty accepts whatever the KeyGenerator's
+	// key type supports.
+	ValidMethods []string
+}
+
+type Option func(*Options)
+
+func WithClockSkew(d time.Duration) Option {
+	return func(o *Options) { o.ClockSkew = d }
+}
+
+func WithValidMethods(methods ...string) Option {
+	return func(o *Options) { o.ValidMethods = methods }
+}
+
+// NewAuthenticator returns an IDInterceptor (app.WithIDInterceptor) that
+// validates the request's bearer token with keyFunc and, on success,
+// stashes its Claims on h for a PermInterceptor or the dispatcher's
+// per-action scope check to read back via ClaimsFromHeader.
+func NewAuthenticator(keyFunc jwt.Keyfunc, opts ...Option) func(h http.Header) error {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	parserOpts := []jwt.ParserOption{jwt.WithLeeway(options.ClockSkew)}
+	if len(options.ValidMethods) > 0 {
+		parserOpts = append(parserOpts, jwt.WithValidMethods(options.ValidMethods))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	return func(h http.Header) error {
+		raw := bearerToken(h.Get("Authorization"))
+		if raw == "" {
+			return ErrMissingToken
+		}
+
+		var claims Claims
+		if _, err := parser.ParseWithClaims(raw, &claims, keyFunc); err != nil {
+			return err
+		}
+
+		encoded, err := encodeClaims(claims)
+		if err != nil {
+			return err
+		}
+		h.Set(claimsHeader, encoded)
+
+		return nil
+	}
+}
+
+func bearerToken(authorization string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authorization, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(authorization, prefix)
+}
@@ -0,0 +1,38 @@
+package jwtauth
+
+import (
+	"errors"
+	"net/http"
+	"path"
+)
+
+// ErrForbidden is returned when the caller authenticated successfully but
+// holds no role whose Policy entry matches the route that was called.
+var ErrForbidden = errors.New("jwtauth: caller's roles are not permitted to call this route")
+
+// Policy maps a role to the route globs (matched with path.Match against the
+// resolved route, e.g. "/v1/orders/create") it's allowed to call. A caller
+// holding several roles is allowed through if any one role's globs match.
+type Policy map[string][]string
+
+// NewAuthorizer returns a PermInterceptor (app.WithPermInterceptor) that
+// denies callers whose token, stashed by an Authenticator ahead of it in the
+// chain, holds no role permitted by policy to call route.
+func NewAuthorizer(policy Policy) func(h http.Header, route string) error {
+	return func(h http.Header, route string) error {
+		claims, ok := ClaimsFromHeader(h)
+		if !ok {
+			return ErrMissingToken
+		}
+
+		for _, role := range claims.Roles {
+			for _, pattern := range policy[role] {
+				if matched, _ := path.Match(pattern, route); matched {
+					return nil
+				}
+			}
+		}
+
+		return ErrForbidden
+	}
+}
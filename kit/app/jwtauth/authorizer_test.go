@@ -0,0 +1,60 @@
+package jwtauth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func headerWithRoles(t *testing.T, roles ...string) http.Header {
+	t.Helper()
+
+	raw, err := encodeClaims(Claims{Roles: roles})
+	if err != nil {
+		t.Fatalf("encodeClaims: %v", err)
+	}
+
+	h := http.Header{}
+	h.Set(claimsHeader, raw)
+	return h
+}
+
+func TestNewAuthorizer_MatchesRouteGlobForHeldRole(t *testing.T) {
+	authorize := NewAuthorizer(Policy{
+		"admin": {"/v1/orders/*"},
+	})
+
+	h := headerWithRoles(t, "admin")
+	if err := authorize(h, "/v1/orders/create"); err != nil {
+		t.Fatalf("expected admin to be allowed, got %v", err)
+	}
+}
+
+func TestNewAuthorizer_RejectsRouteNotCoveredByAnyHeldRolesGlobs(t *testing.T) {
+	authorize := NewAuthorizer(Policy{
+		"admin": {"/v1/orders/*"},
+	})
+
+	h := headerWithRoles(t, "admin")
+	if err := authorize(h, "/v1/accounts/delete"); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestNewAuthorizer_RejectsRoleNotInPolicy(t *testing.T) {
+	authorize := NewAuthorizer(Policy{
+		"admin": {"/v1/orders/*"},
+	})
+
+	h := headerWithRoles(t, "viewer")
+	if err := authorize(h, "/v1/orders/create"); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestNewAuthorizer_RejectsMissingToken(t *testing.T) {
+	authorize := NewAuthorizer(Policy{"admin": {"/v1/orders/*"}})
+
+	if err := authorize(http.Header{}, "/v1/orders/create"); err != ErrMissingToken {
+		t.Fatalf("expected ErrMissingToken, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package jwtauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+)
+
+// Claims is the minimal claim set this package understands. Custom claims
+// beyond Roles/Scopes are available through the embedded RegisteredClaims
+// or by parsing the token again with your own struct.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// HasScope reports whether scope is present verbatim in c.Scopes.
+func (c Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// claimsHeader carries the authenticated caller's claims from the
+// IDInterceptor to the PermInterceptor and the dispatcher's scope check.
+// Both only ever see http.Header, not a context.Context, so this package
+// round-trips claims through a header of its own rather than the request
+// header the client sent.
+const claimsHeader = "X-Circle-Jwt-Claims"
+
+func encodeClaims(c Claims) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeClaims(raw string) (Claims, bool) {
+	var c Claims
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, false
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, false
+	}
+
+	return c, true
+}
+
+// ClaimsFromHeader recovers the Claims an Authenticator stashed on h during
+// its IDInterceptor pass, if any.
+func ClaimsFromHeader(h http.Header) (Claims, bool) {
+	raw := h.Get(claimsHeader)
+	if raw == "" {
+		return Claims{}, false
+	}
+	return decodeClaims(raw)
+}
+
+// StripClaimsHeader deletes claimsHeader from h. h round-trips claims from
+// the IDInterceptor to the dispatcher's scope check, but it's also just an
+// ordinary header name a caller can set on their own request - callers must
+// strip it from every inbound request before running the IDInterceptor, or
+// a forged header lets a caller claim whatever scopes it likes.
+func StripClaimsHeader(h http.Header) {
+	h.Del(claimsHeader)
+}
@@ -0,0 +1,93 @@
+package jwtauth
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sync"
+)
+
+// KeyGenerator supplies the current set of verification keys, keyed by kid.
+// Implementations are free to fetch from a JWKS endpoint, a file on disk
+// polled for rotation, or anything else; NewJWKSKeyFunc only requires Keys
+// to return fresh results whenever the key set may have rotated.
+type KeyGenerator interface {
+	Keys() (map[string]any, error)
+}
+
+// StaticKeys is the simplest KeyGenerator: a fixed kid-to-key map, useful
+// for HS256 shared secrets or tests.
+type StaticKeys map[string]any
+
+func (k StaticKeys) Keys() (map[string]any, error) {
+	return k, nil
+}
+
+// NewJWKSKeyFunc adapts a KeyGenerator into a jwt.Keyfunc, selecting the
+// verification key by the token's kid header so keys can rotate without
+// invalidating tokens signed under a previous key.
+func NewJWKSKeyFunc(source KeyGenerator) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		keys, err := source.Keys()
+		if err != nil {
+			return nil, err
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if len(keys) == 1 {
+				for _, key := range keys {
+					return key, nil
+				}
+			}
+			return nil, fmt.Errorf("jwtauth: token has no kid and key set has %d keys", len(keys))
+		}
+
+		key, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("jwtauth: unknown kid %q", kid)
+		}
+
+		return key, nil
+	}
+}
+
+// cachingKeyGenerator memoizes an upstream KeyGenerator (e.g. a JWKS HTTP
+// fetch) so key rotation checks don't hit the network on every request.
+type cachingKeyGenerator struct {
+	upstream KeyGenerator
+
+	mu     sync.Mutex
+	cached map[string]any
+}
+
+// CacheKeys wraps source so its Keys are fetched once and reused until
+// Invalidate is called, e.g. from a background refresh ticker.
+func CacheKeys(source KeyGenerator) interface {
+	KeyGenerator
+	Invalidate()
+} {
+	return &cachingKeyGenerator{upstream: source}
+}
+
+func (c *cachingKeyGenerator) Keys() (map[string]any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil {
+		return c.cached, nil
+	}
+
+	keys, err := c.upstream.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	c.cached = keys
+	return keys, nil
+}
+
+func (c *cachingKeyGenerator) Invalidate() {
+	c.mu.Lock()
+	c.cached = nil
+	c.mu.Unlock()
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
@@ -12,6 +13,7 @@ import (
 	"github.com/lucas-clemente/quic-go/http3"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/stacktasec/circle/kit/core/biz"
 	"github.com/stacktasec/circle/zlog"
 	"io/fs"
 	"net/http"
@@ -33,29 +35,16 @@ type Request interface {
 	Validate() error
 }
 
-type knownError struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-}
-
-func (k knownError) Error() string {
-	return fmt.Sprintf("[Status] %s [Message] %s", k.Status, k.Message)
-}
-
-func (k knownError) Is(err error) bool {
-	nErr, ok := err.(knownError)
-	if !ok {
-		return false
-	}
-
-	return k.Status == nErr.Status && k.Message == nErr.Message
-}
+// knownError is biz.Error under its original name, kept so the rest of this
+// file (and any caller type-asserting on it) doesn't need to change when the
+// error taxonomy moved into biz.
+type knownError = biz.Error
 
+// MakeKnownError preserves the original two-field constructor; new code
+// should prefer biz.New or one of its Code-specific constructors, which also
+// set the HTTP status fillActions responds with.
 func MakeKnownError(status, message string) error {
-	return knownError{
-		Status:  status,
-		Message: message,
-	}
+	return biz.New(biz.Code(status), message)
 }
 
 type versionGroup struct {
@@ -368,16 +357,17 @@ func (a *app) fillActions(g *gin.RouterGroup, service any) {
 
 			// 判断第二个值 是自定义错误
 			// 还是原生error
-			errValue := rtnList[1].Interface()
+			errValue, _ := rtnList[1].Interface().(error)
 			if errValue != nil {
 				if errValue == context.DeadlineExceeded {
 					c.AbortWithStatus(http.StatusGatewayTimeout)
 					return
 				}
 
-				err, ok := errValue.(knownError)
-				if ok {
-					c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err})
+				var err knownError
+				if errors.As(errValue, &err) {
+					err = err.WithRequestID(reqID)
+					c.AbortWithStatusJSON(err.Code.HTTPStatus(), gin.H{"error": err})
 					return
 				} else {
 					c.AbortWithStatus(http.StatusInternalServerError)
@@ -1,28 +1,143 @@
 package biz
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
-func MakeError(status, message string) error {
-	return Error{
-		Status:  status,
-		Message: message,
+// Code is a canonical error category, modeled on gRPC's status codes, so a
+// dispatcher can map it onto a specific HTTP status instead of collapsing
+// every business error into a single 409 the way the original Status-only
+// design did.
+type Code string
+
+const (
+	CodeNotFound          Code = "not_found"
+	CodeAlreadyExists     Code = "already_exists"
+	CodePermissionDenied  Code = "permission_denied"
+	CodeInvalidArgument   Code = "invalid_argument"
+	CodeResourceExhausted Code = "resource_exhausted"
+	CodeUnavailable       Code = "unavailable"
+	CodeInternal          Code = "internal"
+	CodeDeadlineExceeded  Code = "deadline_exceeded"
+	CodeUnauthenticated   Code = "unauthenticated"
+)
+
+// httpStatus is the canonical Code -> HTTP status mapping. A Code missing
+// from the table (including the zero Code) falls back to 500.
+var httpStatus = map[Code]int{
+	CodeNotFound:          http.StatusNotFound,
+	CodeAlreadyExists:     http.StatusConflict,
+	CodePermissionDenied:  http.StatusForbidden,
+	CodeInvalidArgument:   http.StatusBadRequest,
+	CodeResourceExhausted: http.StatusTooManyRequests,
+	CodeUnavailable:       http.StatusServiceUnavailable,
+	CodeInternal:          http.StatusInternalServerError,
+	CodeDeadlineExceeded:  http.StatusGatewayTimeout,
+	CodeUnauthenticated:   http.StatusUnauthorized,
+}
+
+// HTTPStatus is the status a dispatcher should respond with for c.
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatus[c]; ok {
+		return status
 	}
+	return http.StatusInternalServerError
+}
+
+// FieldViolation describes one invalid field, carried by InvalidArgument
+// errors so a client can point at the exact field instead of parsing
+// Message.
+type FieldViolation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
 }
 
+// Error is a categorized business error. Code drives the HTTP status a
+// dispatcher responds with, Message is safe to show a caller, Details holds
+// FieldViolations for CodeInvalidArgument, RequestID is filled in by the
+// dispatcher handling the request, and TraceID is set only by an explicit
+// WithTraceID call; the dispatcher leaves it empty. cause preserves whatever error
+// was wrapped, if any, so errors.Is/errors.As still see through to it.
 type Error struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Code      Code             `json:"code"`
+	Status    string           `json:"status"`
+	Message   string           `json:"message"`
+	Details   []FieldViolation `json:"details,omitempty"`
+	RequestID string           `json:"request_id,omitempty"`
+	TraceID   string           `json:"trace_id,omitempty"`
+
+	cause error
 }
 
 func (e Error) Error() string {
-	return fmt.Sprintf("[Status] %s [Message] %s", e.Status, e.Message)
+	if e.cause != nil {
+		return fmt.Sprintf("[%s] %s: %s", e.Code, e.Message, e.cause)
+	}
+	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
 }
 
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches on Code alone, so errors.Is(err, biz.NotFound("")) works as a
+// category check regardless of Message, consistent with circle.Error.Is.
 func (e Error) Is(err error) bool {
-	nErr, ok := err.(Error)
-	if !ok {
+	var nErr Error
+	if !errors.As(err, &nErr) {
 		return false
 	}
+	return e.Code == nErr.Code
+}
 
-	return e.Status == nErr.Status && e.Message == nErr.Message
+// New builds an Error of the given Code, the constructor every Code-named
+// helper below (NotFound, Internal, ...) delegates to.
+func New(code Code, message string) Error {
+	return Error{Code: code, Status: string(code), Message: message}
+}
+
+// Wrap attaches code to cause, preserving cause as Unwrap's result so
+// errors.Is/errors.As see through Error to whatever it wrapped.
+func Wrap(code Code, cause error) Error {
+	return Error{Code: code, Status: string(code), Message: cause.Error(), cause: cause}
+}
+
+func NotFound(message string) Error          { return New(CodeNotFound, message) }
+func AlreadyExists(message string) Error     { return New(CodeAlreadyExists, message) }
+func PermissionDenied(message string) Error  { return New(CodePermissionDenied, message) }
+func ResourceExhausted(message string) Error { return New(CodeResourceExhausted, message) }
+func Unavailable(message string) Error       { return New(CodeUnavailable, message) }
+func Internal(message string) Error          { return New(CodeInternal, message) }
+func DeadlineExceeded(message string) Error  { return New(CodeDeadlineExceeded, message) }
+func Unauthenticated(message string) Error   { return New(CodeUnauthenticated, message) }
+
+// InvalidArgument builds a CodeInvalidArgument Error carrying per-field
+// violations.
+func InvalidArgument(message string, violations ...FieldViolation) Error {
+	e := New(CodeInvalidArgument, message)
+	e.Details = violations
+	return e
+}
+
+// WithRequestID returns a copy of e stamped with the request id of whatever
+// call produced it; dispatchers call this right before encoding the error
+// response.
+func (e Error) WithRequestID(id string) Error {
+	e.RequestID = id
+	return e
+}
+
+// WithTraceID returns a copy of e stamped with a trace id.
+func (e Error) WithTraceID(id string) Error {
+	e.TraceID = id
+	return e
+}
+
+// MakeError preserves the original two-field constructor for callers that
+// only have a status string and message; new code should prefer New or one
+// of the Code-specific constructors above.
+func MakeError(status, message string) error {
+	return New(Code(status), message)
 }
@@ -24,4 +24,10 @@ type Hub interface {
 	OnMessage(fn func(Session, []byte))
 	OnError(fn func(Session, error))
 	OnClose(fn func(Session, int))
+
+	// ServeWS and ServeSSE are the transport entry points internal/kit/core's
+	// App.MapHub mounts as routes; each upgrades the request to its own
+	// session kind and drives it until the client disconnects.
+	ServeWS(w http.ResponseWriter, r *http.Request)
+	ServeSSE(w http.ResponseWriter, r *http.Request)
 }
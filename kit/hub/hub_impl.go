@@ -0,0 +1,111 @@
+package hub
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// hubImpl is the concrete Hub built by New. It owns the sharded session
+// registry and the OnUpgrade/OnMessage/OnError/OnClose callbacks the
+// transports invoke as sessions come and go.
+type hubImpl struct {
+	options Options
+	reg     *registry
+
+	onUpgrade http.HandlerFunc
+	onMessage func(Session, []byte)
+	onError   func(Session, error)
+	onClose   func(Session, int)
+}
+
+// New builds a Hub. Mount it alongside RPC routes with internal/kit/core's
+// App.MapHub, or call ServeWS/ServeSSE directly from a plain net/http handler.
+func New(opts ...Option) Hub {
+	var options Options
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+	options.Ensure()
+
+	return &hubImpl{
+		options: options,
+		reg:     newRegistry(options.Shards),
+	}
+}
+
+func (h *hubImpl) Register(session Session) error {
+	h.reg.add(session)
+	return nil
+}
+
+func (h *hubImpl) Unregister(session Session) error {
+	_, ok := h.reg.remove(session.ID())
+	if !ok {
+		return nil
+	}
+	return session.Close()
+}
+
+func (h *hubImpl) Send(id string, msg []byte) error {
+	session, ok := h.reg.get(id)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	return h.enqueue(session, msg)
+}
+
+// Broadcast runs fn concurrently across every registered session, so a slow
+// predicate evaluated for one session never delays delivery to the rest.
+func (h *hubImpl) Broadcast(fn func(Session) bool, msg []byte) error {
+	var wg sync.WaitGroup
+
+	h.reg.each(func(session Session) {
+		wg.Add(1)
+		go func(s Session) {
+			defer wg.Done()
+			if fn(s) {
+				_ = h.enqueue(s, msg)
+			}
+		}(session)
+	})
+
+	wg.Wait()
+	return nil
+}
+
+// queuer is implemented by baseSession; enqueue uses it so Send/Broadcast
+// buffer through the session's outbound queue instead of writing to the
+// connection from an arbitrary caller goroutine.
+type queuer interface {
+	queue() *outboundQueue
+}
+
+func (h *hubImpl) enqueue(session Session, msg []byte) error {
+	q, ok := session.(queuer)
+	if !ok {
+		_, err := session.Write(msg)
+		return err
+	}
+
+	err := q.queue().enqueue(msg)
+	if err == ErrDisconnected {
+		_ = h.Unregister(session)
+	}
+	return err
+}
+
+func (h *hubImpl) OnUpgrade(fn http.HandlerFunc)      { h.onUpgrade = fn }
+func (h *hubImpl) OnMessage(fn func(Session, []byte)) { h.onMessage = fn }
+func (h *hubImpl) OnError(fn func(Session, error))    { h.onError = fn }
+func (h *hubImpl) OnClose(fn func(Session, int))      { h.onClose = fn }
+
+// tickerC lets the write pumps select on a possibly-nil heartbeat ticker
+// without a second branch: a nil channel simply never fires.
+func tickerC(t *time.Ticker) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
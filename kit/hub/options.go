@@ -0,0 +1,87 @@
+package hub
+
+import "time"
+
+// BackpressurePolicy controls what a session's outbound queue does once it
+// fills up: either drop the oldest queued message to make room for the new
+// one, or close the session outright so a slow reader can't build unbounded
+// memory behind it.
+type BackpressurePolicy int
+
+const (
+	DropOldest BackpressurePolicy = iota
+	Disconnect
+)
+
+// Options configures a Hub built by New. Zero values are replaced by
+// sensible defaults in Ensure.
+type Options struct {
+	// Shards is the number of registry shards sessions are hashed across.
+	// More shards means less lock contention under concurrent
+	// Register/Unregister/Send, at the cost of Broadcast needing to walk
+	// that many maps.
+	Shards int
+
+	// QueueSize bounds each session's outbound message queue.
+	QueueSize int
+
+	// Backpressure decides what happens once a session's queue is full.
+	Backpressure BackpressurePolicy
+
+	// HeartbeatInterval is how often a ping is sent to idle sessions. Zero
+	// disables heartbeats.
+	HeartbeatInterval time.Duration
+
+	// PongTimeout is how long a session may go without answering a ping
+	// before it's considered dead and unregistered.
+	PongTimeout time.Duration
+}
+
+func (o *Options) Ensure() {
+	if o.Shards == 0 {
+		o.Shards = 16
+	}
+
+	if o.QueueSize == 0 {
+		o.QueueSize = 256
+	}
+
+	if o.HeartbeatInterval > 0 && o.PongTimeout == 0 {
+		o.PongTimeout = o.HeartbeatInterval * 2
+	}
+}
+
+type Option interface {
+	Apply(*Options)
+}
+
+type OptionFunc func(opts *Options)
+
+func (opt OptionFunc) Apply(opts *Options) {
+	opt(opts)
+}
+
+func WithShards(shards int) Option {
+	return OptionFunc(func(opts *Options) {
+		opts.Shards = shards
+	})
+}
+
+func WithQueueSize(size int) Option {
+	return OptionFunc(func(opts *Options) {
+		opts.QueueSize = size
+	})
+}
+
+func WithBackpressure(policy BackpressurePolicy) Option {
+	return OptionFunc(func(opts *Options) {
+		opts.Backpressure = policy
+	})
+}
+
+func WithHeartbeat(interval, pongTimeout time.Duration) Option {
+	return OptionFunc(func(opts *Options) {
+		opts.HeartbeatInterval = interval
+		opts.PongTimeout = pongTimeout
+	})
+}
@@ -0,0 +1,73 @@
+package hub
+
+import "errors"
+
+// ErrQueueClosed is returned by enqueue once the session's outbound queue
+// has already been closed.
+var ErrQueueClosed = errors.New("hub: session queue closed")
+
+// ErrDisconnected is returned by enqueue when the queue is full and the
+// hub's backpressure policy is Disconnect.
+var ErrDisconnected = errors.New("hub: session disconnected under backpressure")
+
+// ErrSessionNotFound is returned by Send when no session with the given id
+// is registered.
+var ErrSessionNotFound = errors.New("hub: session not found")
+
+// outboundQueue buffers messages destined for one session so a slow reader
+// can't block whichever goroutine produced the message, e.g. Broadcast.
+type outboundQueue struct {
+	policy BackpressurePolicy
+	ch     chan []byte
+	closed chan struct{}
+}
+
+func newOutboundQueue(size int, policy BackpressurePolicy) *outboundQueue {
+	return &outboundQueue{
+		policy: policy,
+		ch:     make(chan []byte, size),
+		closed: make(chan struct{}),
+	}
+}
+
+// enqueue buffers msg for delivery. Under DropOldest it makes room by
+// discarding the queue's oldest pending message instead of blocking the
+// caller; under Disconnect it closes the queue and reports ErrDisconnected
+// so the caller can unregister the session.
+func (q *outboundQueue) enqueue(msg []byte) error {
+	select {
+	case <-q.closed:
+		return ErrQueueClosed
+	default:
+	}
+
+	select {
+	case q.ch <- msg:
+		return nil
+	default:
+	}
+
+	switch q.policy {
+	case Disconnect:
+		q.close()
+		return ErrDisconnected
+	default: // DropOldest
+		select {
+		case <-q.ch:
+		default:
+		}
+		select {
+		case q.ch <- msg:
+		default:
+		}
+		return nil
+	}
+}
+
+func (q *outboundQueue) close() {
+	select {
+	case <-q.closed:
+	default:
+		close(q.closed)
+	}
+}
@@ -0,0 +1,45 @@
+package hub
+
+import "testing"
+
+// TestOutboundQueue_DropOldestMakesRoomForNewest verifies DropOldest evicts
+// the oldest pending message rather than blocking the producer or rejecting
+// the newest one.
+func TestOutboundQueue_DropOldestMakesRoomForNewest(t *testing.T) {
+	q := newOutboundQueue(2, DropOldest)
+
+	for _, msg := range [][]byte{[]byte("1"), []byte("2"), []byte("3")} {
+		if err := q.enqueue(msg); err != nil {
+			t.Fatalf("enqueue(%s): %v", msg, err)
+		}
+	}
+
+	first := <-q.ch
+	if string(first) != "2" {
+		t.Fatalf("expected oldest message \"1\" to have been dropped, got %q first", first)
+	}
+
+	second := <-q.ch
+	if string(second) != "3" {
+		t.Fatalf("expected \"3\" to still be queued, got %q", second)
+	}
+}
+
+// TestOutboundQueue_DisconnectClosesOnFullQueue verifies the Disconnect
+// policy closes the queue and reports ErrDisconnected instead of silently
+// dropping a message, so the caller can unregister the session.
+func TestOutboundQueue_DisconnectClosesOnFullQueue(t *testing.T) {
+	q := newOutboundQueue(1, Disconnect)
+
+	if err := q.enqueue([]byte("1")); err != nil {
+		t.Fatalf("enqueue(1): %v", err)
+	}
+
+	if err := q.enqueue([]byte("2")); err != ErrDisconnected {
+		t.Fatalf("expected ErrDisconnected once the queue is full, got %v", err)
+	}
+
+	if err := q.enqueue([]byte("3")); err != ErrQueueClosed {
+		t.Fatalf("expected ErrQueueClosed after Disconnect closed the queue, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package hub
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// shard guards one slice of the session space behind its own lock, so
+// Register/Unregister/Send on sessions hashing to different shards never
+// contend with each other.
+type shard struct {
+	mu       sync.RWMutex
+	sessions map[string]Session
+}
+
+// registry is a Session set sharded by id hash, trading Broadcast's need to
+// walk every shard for much lower contention on the hot Register/Send path.
+type registry struct {
+	shards []*shard
+}
+
+func newRegistry(shardCount int) *registry {
+	shards := make([]*shard, shardCount)
+	for i := range shards {
+		shards[i] = &shard{sessions: make(map[string]Session)}
+	}
+
+	return &registry{shards: shards}
+}
+
+func (r *registry) shardFor(id string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return r.shards[h.Sum32()%uint32(len(r.shards))]
+}
+
+func (r *registry) add(session Session) {
+	s := r.shardFor(session.ID())
+	s.mu.Lock()
+	s.sessions[session.ID()] = session
+	s.mu.Unlock()
+}
+
+func (r *registry) remove(id string) (Session, bool) {
+	s := r.shardFor(id)
+	s.mu.Lock()
+	session, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+	return session, ok
+}
+
+func (r *registry) get(id string) (Session, bool) {
+	s := r.shardFor(id)
+	s.mu.RLock()
+	session, ok := s.sessions[id]
+	s.mu.RUnlock()
+	return session, ok
+}
+
+// each calls fn for every registered session. fn is called with the shard
+// lock released, so it may safely call back into the registry.
+func (r *registry) each(fn func(Session)) {
+	for _, s := range r.shards {
+		s.mu.RLock()
+		sessions := make([]Session, 0, len(s.sessions))
+		for _, session := range s.sessions {
+			sessions = append(sessions, session)
+		}
+		s.mu.RUnlock()
+
+		for _, session := range sessions {
+			fn(session)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package hub
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type fakeSession struct {
+	baseSession
+}
+
+func newFakeSession(id string) *fakeSession {
+	bs := newBaseSession(id, nil, DropOldest, 1)
+	return &fakeSession{baseSession: bs}
+}
+
+func (*fakeSession) Read(p []byte) (int, error)  { return 0, nil }
+func (*fakeSession) Write(p []byte) (int, error) { return len(p), nil }
+func (*fakeSession) Close() error                { return nil }
+
+// TestRegistry_AddGetRemove verifies the basic lifecycle a transport relies
+// on: a session is retrievable by id once added, and gone once removed.
+func TestRegistry_AddGetRemove(t *testing.T) {
+	r := newRegistry(4)
+	s := newFakeSession("one")
+
+	r.add(s)
+
+	got, ok := r.get("one")
+	if !ok || got.ID() != "one" {
+		t.Fatalf("expected to find session \"one\", got %v, %v", got, ok)
+	}
+
+	removed, ok := r.remove("one")
+	if !ok || removed.ID() != "one" {
+		t.Fatalf("expected remove to return session \"one\", got %v, %v", removed, ok)
+	}
+
+	if _, ok := r.get("one"); ok {
+		t.Fatal("expected session to be gone after remove")
+	}
+}
+
+// TestRegistry_Each verifies each visits every session across every shard,
+// not just the shard a given id happens to hash to.
+func TestRegistry_Each(t *testing.T) {
+	r := newRegistry(4)
+
+	want := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		r.add(newFakeSession(id))
+		want[id] = true
+	}
+
+	got := map[string]bool{}
+	r.each(func(s Session) {
+		got[s.ID()] = true
+	})
+
+	if len(got) != len(want) {
+		t.Fatalf("expected each to visit %d sessions, visited %d", len(want), len(got))
+	}
+	for id := range want {
+		if !got[id] {
+			t.Fatalf("each never visited session %s", id)
+		}
+	}
+}
+
+// TestRegistry_ConcurrentAddRemoveGet exercises the sharded locking under
+// concurrent access; run with -race to catch a shard's lock being skipped.
+func TestRegistry_ConcurrentAddRemoveGet(t *testing.T) {
+	r := newRegistry(8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("session-%d", i)
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			r.add(newFakeSession(id))
+			r.get(id)
+			r.remove(id)
+		}(id)
+	}
+	wg.Wait()
+}
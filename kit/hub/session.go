@@ -0,0 +1,47 @@
+package hub
+
+import (
+	"net/http"
+	"sync"
+)
+
+// baseSession implements the identity/storage half of Session; transports
+// embed it and supply Read/Write/Close themselves over whatever connection
+// they hold.
+type baseSession struct {
+	id      string
+	request *http.Request
+	store   sync.Map
+
+	outbound *outboundQueue
+}
+
+func newBaseSession(id string, r *http.Request, policy BackpressurePolicy, queueSize int) baseSession {
+	return baseSession{
+		id:       id,
+		request:  r,
+		outbound: newOutboundQueue(queueSize, policy),
+	}
+}
+
+func (s *baseSession) ID() string {
+	return s.id
+}
+
+func (s *baseSession) Request() *http.Request {
+	return s.request
+}
+
+func (s *baseSession) Set(key string, value any) {
+	s.store.Store(key, value)
+}
+
+func (s *baseSession) Get(key string) (any, bool) {
+	return s.store.Load(key)
+}
+
+// queue exposes the session's outbound queue so hubImpl can buffer
+// Send/Broadcast traffic instead of writing to the connection directly.
+func (s *baseSession) queue() *outboundQueue {
+	return s.outbound
+}
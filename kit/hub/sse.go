@@ -0,0 +1,107 @@
+package hub
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sseSession is write-only: Server-Sent Events is a one-way server push, so
+// Read always reports io.EOF.
+type sseSession struct {
+	baseSession
+	writer  *bufio.Writer
+	flusher http.Flusher
+}
+
+func (s *sseSession) Read(p []byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (s *sseSession) Write(p []byte) (int, error) {
+	if _, err := fmt.Fprintf(s.writer, "data: %s\n\n", p); err != nil {
+		return 0, err
+	}
+	if err := s.writer.Flush(); err != nil {
+		return 0, err
+	}
+	s.flusher.Flush()
+	return len(p), nil
+}
+
+func (s *sseSession) Close() error {
+	s.outbound.close()
+	return nil
+}
+
+// ServeSSE registers an SSE session and blocks, flushing queued Send/
+// Broadcast messages and periodic heartbeat comments to the client until
+// its request context is cancelled.
+func (h *hubImpl) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	if h.onUpgrade != nil {
+		h.onUpgrade(w, r)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	session := &sseSession{
+		baseSession: newBaseSession(uuid.NewString(), r, h.options.Backpressure, h.options.QueueSize),
+		writer:      bufio.NewWriter(w),
+		flusher:     flusher,
+	}
+
+	if err := h.Register(session); err != nil {
+		return
+	}
+
+	defer func() {
+		_ = h.Unregister(session)
+		if h.onClose != nil {
+			h.onClose(session, 0)
+		}
+	}()
+
+	var ticker *time.Ticker
+	if h.options.HeartbeatInterval > 0 {
+		ticker = time.NewTicker(h.options.HeartbeatInterval)
+		defer ticker.Stop()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-session.outbound.ch:
+			if !ok {
+				return
+			}
+			if _, err := session.Write(msg); err != nil {
+				if h.onError != nil {
+					h.onError(session, err)
+				}
+				return
+			}
+		case <-session.outbound.closed:
+			return
+		case <-tickerC(ticker):
+			if _, err := fmt.Fprint(session.writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			_ = session.writer.Flush()
+			flusher.Flush()
+		}
+	}
+}
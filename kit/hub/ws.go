@@ -0,0 +1,127 @@
+package hub
+
+import (
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"time"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// wsSession adapts a *websocket.Conn to Session. Reads/writes go straight
+// to the connection; the hub's own Send/Broadcast traffic instead flows
+// through the embedded outbound queue, drained by writePumpWS.
+type wsSession struct {
+	baseSession
+	conn *websocket.Conn
+}
+
+func (s *wsSession) Read(p []byte) (int, error) {
+	_, data, err := s.conn.ReadMessage()
+	if err != nil {
+		return 0, err
+	}
+	return copy(p, data), nil
+}
+
+func (s *wsSession) Write(p []byte) (int, error) {
+	if err := s.conn.WriteMessage(websocket.TextMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *wsSession) Close() error {
+	s.outbound.close()
+	return s.conn.Close()
+}
+
+// ServeWS upgrades r to a WebSocket session, registers it, and blocks
+// driving its read/write pumps until the client disconnects.
+func (h *hubImpl) ServeWS(w http.ResponseWriter, r *http.Request) {
+	if h.onUpgrade != nil {
+		h.onUpgrade(w, r)
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	session := &wsSession{
+		baseSession: newBaseSession(uuid.NewString(), r, h.options.Backpressure, h.options.QueueSize),
+		conn:        conn,
+	}
+
+	if err := h.Register(session); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	go h.writePumpWS(session)
+	h.readPumpWS(session)
+}
+
+func (h *hubImpl) writePumpWS(s *wsSession) {
+	var ticker *time.Ticker
+	if h.options.HeartbeatInterval > 0 {
+		ticker = time.NewTicker(h.options.HeartbeatInterval)
+		defer ticker.Stop()
+	}
+
+	for {
+		select {
+		case msg, ok := <-s.outbound.ch:
+			if !ok {
+				return
+			}
+			if err := s.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				if h.onError != nil {
+					h.onError(s, err)
+				}
+				return
+			}
+		case <-s.outbound.closed:
+			return
+		case <-tickerC(ticker):
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *hubImpl) readPumpWS(s *wsSession) {
+	if h.options.PongTimeout > 0 {
+		_ = s.conn.SetReadDeadline(time.Now().Add(h.options.PongTimeout))
+		s.conn.SetPongHandler(func(string) error {
+			return s.conn.SetReadDeadline(time.Now().Add(h.options.PongTimeout))
+		})
+	}
+
+	defer func() {
+		_ = h.Unregister(s)
+		if h.onClose != nil {
+			h.onClose(s, websocket.CloseNormalClosure)
+		}
+	}()
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if h.onError != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.onError(s, err)
+			}
+			return
+		}
+
+		if h.onMessage != nil {
+			h.onMessage(s, data)
+		}
+	}
+}
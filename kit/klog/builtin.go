@@ -1,6 +1,7 @@
 package klog
 
 import (
+	"context"
 	"fmt"
 	"github.com/stacktasec/circle/kit/klog/zap"
 )
@@ -36,6 +37,38 @@ func Fatal(format any, a ...any) {
 	builtinLogger.Fatal(msg)
 }
 
+func Debugw(msg string, keysAndValues ...any) {
+	builtinLogger.Debugw(msg, keysAndValues...)
+}
+
+func Infow(msg string, keysAndValues ...any) {
+	builtinLogger.Infow(msg, keysAndValues...)
+}
+
+func Warnw(msg string, keysAndValues ...any) {
+	builtinLogger.Warnw(msg, keysAndValues...)
+}
+
+func Errorw(msg string, keysAndValues ...any) {
+	builtinLogger.Errorw(msg, keysAndValues...)
+}
+
 func SyncLogger() error {
 	return builtinLogger.Sync()
 }
+
+// With returns a Logger built from the package-level logger with fields
+// attached to every subsequent line.
+func With(fields ...Field) Logger {
+	return builtinLogger.With(fields...)
+}
+
+// Ctx returns a Logger carrying whatever fields were stashed on ctx.
+func Ctx(ctx context.Context) Logger {
+	return builtinLogger.Ctx(ctx)
+}
+
+// FromContext is an alias of Ctx for callers that prefer that name.
+func FromContext(ctx context.Context) Logger {
+	return Ctx(ctx)
+}
@@ -0,0 +1,15 @@
+package klog
+
+import "github.com/stacktasec/circle/kit/klog/internal"
+
+type Field = internal.Field
+
+var (
+	String            = internal.String
+	Int               = internal.Int
+	Any               = internal.Any
+	Err               = internal.Err
+	RequestID         = internal.RequestID
+	TraceID           = internal.TraceID
+	ContextWithFields = internal.ContextWithFields
+)
@@ -0,0 +1,85 @@
+package internal
+
+import "context"
+
+// Field and Logger live here, rather than in klog, so that kit/klog/zap can
+// implement Logger without importing klog and creating an import cycle.
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err wraps err under the conventional "error" key; nil-safe so callers can
+// pass a possibly-nil error straight from a return value.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// RequestID builds the field fillActions attaches to every request's
+// context, and TraceID its trace-id counterpart (which fillActions does not
+// attach), named consistently so With(RequestID(id)) and
+// ContextWithFields(ctx, RequestID(id)) produce the same JSON key.
+func RequestID(id string) Field {
+	return Field{Key: "request_id", Value: id}
+}
+
+func TraceID(id string) Field {
+	return Field{Key: "trace_id", Value: id}
+}
+
+type Logger interface {
+	Debug(format any, a ...any)
+	Info(format any, a ...any)
+	Warn(format any, a ...any)
+	Error(format any, a ...any)
+	Fatal(format any, a ...any)
+
+	// Debugw, Infow, Warnw, and Errorw log msg with an alternating
+	// key/value tail (keysAndValues[0] is a key, keysAndValues[1] its
+	// value, and so on), for callers that want structured fields without
+	// building Field values by hand.
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+
+	Sync() error
+
+	With(fields ...Field) Logger
+	Ctx(ctx context.Context) Logger
+
+	// SetLevel and Level let operators change a running service's log
+	// level without a restart, e.g. via klog.LevelHandler.
+	SetLevel(level string)
+	Level() string
+}
+
+type ctxKeyFields struct{}
+
+// ContextWithFields stashes fields on ctx so a later Ctx(ctx) call can pick
+// them back up.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	if existing, ok := ctx.Value(ctxKeyFields{}).([]Field); ok {
+		fields = append(append([]Field{}, existing...), fields...)
+	}
+
+	return context.WithValue(ctx, ctxKeyFields{}, fields)
+}
+
+func FieldsFromContext(ctx context.Context) []Field {
+	fields, _ := ctx.Value(ctxKeyFields{}).([]Field)
+	return fields
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "io"
+
 const (
 	LevelDebug = "debug"
 	LevelInfo  = "info"
@@ -24,18 +26,51 @@ type Options struct {
 	Json       bool
 
 	CallerSkip int
+
+	// SamplingInitial/SamplingThereafter mirror zap.Config.Sampling: once
+	// SamplingInitial identical lines are logged within a second, only
+	// every SamplingThereafter-th one after that is kept. Zero disables
+	// sampling.
+	SamplingInitial    int
+	SamplingThereafter int
+
+	// FilePath, set via WithFile, adds a file sink alongside stdout.
+	// Rotation, set via WithRotation, makes that sink a lumberjack-rotated
+	// writer instead of a plain append-only file.
+	FilePath string
+	Rotation *Rotation
+
+	// Writers holds additional sinks registered via WithWriter; each gets
+	// its own core in the tee, at the same level and encoding as stdout.
+	Writers []io.Writer
 }
 
-func (o *Options) Ensure() {
-	switch o.Level {
+// Rotation configures lumberjack's rotation policy for the file sink.
+type Rotation struct {
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+}
+
+// IsValidLevel reports whether level is one of the five well-known levels.
+// LevelHandler checks this before calling SetLevel so a malformed PUT
+// returns 400 instead of reaching zap with a level it can't convert.
+func IsValidLevel(level string) bool {
+	switch level {
 	case LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal:
+		return true
 	default:
+		return false
+	}
+}
+
+func (o *Options) Ensure() {
+	if !IsValidLevel(o.Level) {
 		o.Level = LevelDebug
 	}
 
-	switch o.Stacktrace {
-	case LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal:
-	default:
+	if !IsValidLevel(o.Stacktrace) {
 		o.Stacktrace = LevelError
 	}
 
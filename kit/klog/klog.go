@@ -1,18 +1,15 @@
 package klog
 
 import (
+	"io"
+
 	"github.com/stacktasec/circle/kit/klog/internal"
 	"github.com/stacktasec/circle/kit/klog/zap"
 )
 
-type Logger interface {
-	Debug(format any, a ...any)
-	Info(format any, a ...any)
-	Warn(format any, a ...any)
-	Error(format any, a ...any)
-	Fatal(format any, a ...any)
-	Sync() error
-}
+// Logger is an alias of internal.Logger so kit/klog/zap can implement it
+// without importing this package back.
+type Logger = internal.Logger
 
 var _ Logger = (*zap.Logger)(nil)
 
@@ -39,3 +36,43 @@ func internalWithSkip(skip int) internal.Option {
 		opts.CallerSkip = skip
 	})
 }
+
+// WithSampling caps log volume: after initial identical lines are logged
+// within a second, only every thereafter-th one is kept. Guards against a
+// runaway handler flooding stdout.
+func WithSampling(initial, thereafter int) internal.Option {
+	return internal.LogOptionFunc(func(opts *internal.Options) {
+		opts.SamplingInitial = initial
+		opts.SamplingThereafter = thereafter
+	})
+}
+
+// WithFile adds a file sink at path alongside stdout. Combine with
+// WithRotation to roll it over instead of letting it grow unbounded.
+func WithFile(path string) internal.Option {
+	return internal.LogOptionFunc(func(opts *internal.Options) {
+		opts.FilePath = path
+	})
+}
+
+// WithRotation rolls WithFile's file over once it reaches maxSizeMB,
+// keeping at most maxBackups old copies for at most maxAgeDays, optionally
+// gzip-compressed. Has no effect without WithFile.
+func WithRotation(maxSizeMB, maxBackups, maxAgeDays int, compress bool) internal.Option {
+	return internal.LogOptionFunc(func(opts *internal.Options) {
+		opts.Rotation = &internal.Rotation{
+			MaxSizeMB:  maxSizeMB,
+			MaxBackups: maxBackups,
+			MaxAgeDays: maxAgeDays,
+			Compress:   compress,
+		}
+	})
+}
+
+// WithWriter adds an additional sink, logged to at the same level and
+// encoding as stdout.
+func WithWriter(w io.Writer) internal.Option {
+	return internal.LogOptionFunc(func(opts *internal.Options) {
+		opts.Writers = append(opts.Writers, w)
+	})
+}
@@ -0,0 +1,39 @@
+package klog
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stacktasec/circle/kit/klog/internal"
+)
+
+// levelBody is the JSON shape LevelHandler reads and writes.
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler returns an http.Handler that reports the package-level
+// logger's current level on GET and changes it on PUT, letting operators
+// flip a running service from info to debug without a restart.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(levelBody{Level: builtinLogger.Level()})
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if !internal.IsValidLevel(body.Level) {
+				http.Error(w, "unknown level: "+body.Level, http.StatusBadRequest)
+				return
+			}
+			builtinLogger.SetLevel(body.Level)
+			json.NewEncoder(w).Encode(levelBody{Level: builtinLogger.Level()})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// Package middleware provides Gin and gRPC request-logging wrappers built
+// on top of kit/klog, mirroring the gin/grpc-zap middlewares common to other
+// zap-based wrappers so services get structured access logs for free.
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stacktasec/circle/kit/klog"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// Gin logs every request through klog with method, path, status, latency,
+// remote address, and request id fields, recovering from panics and
+// logging them at Error with a stacktrace instead of crashing the process.
+func Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				klog.With(
+					klog.String("method", c.Request.Method),
+					klog.String("path", c.Request.URL.Path),
+					klog.RequestID(c.Writer.Header().Get(requestIDHeader)),
+					klog.Any("panic", r),
+					klog.String("stacktrace", string(debug.Stack())),
+				).Error("panic recovered")
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+
+		c.Next()
+
+		logger := klog.With(
+			klog.String("method", c.Request.Method),
+			klog.String("path", c.Request.URL.Path),
+			klog.Int("status", c.Writer.Status()),
+			klog.Any("latency", time.Since(start)),
+			klog.String("remote_addr", c.ClientIP()),
+			klog.RequestID(c.Writer.Header().Get(requestIDHeader)),
+		)
+
+		switch {
+		case c.Writer.Status() >= http.StatusInternalServerError:
+			logger.Error("request failed")
+		case c.Writer.Status() >= http.StatusBadRequest:
+			logger.Warn("request failed")
+		default:
+			logger.Info("request handled")
+		}
+	}
+}
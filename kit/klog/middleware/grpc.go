@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/stacktasec/circle/kit/klog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs each unary RPC through klog with full method,
+// status code, and latency fields, recovering panics and logging them at
+// Error with a stacktrace so a single handler panic can't take the server
+// down silently.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				klog.With(
+					klog.String("full_method", info.FullMethod),
+					klog.Any("panic", r),
+					klog.String("stacktrace", string(debug.Stack())),
+				).Error("panic recovered")
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+
+		logRPC(ctx, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor's streaming counterpart,
+// logging once the stream handler returns since a stream has no single
+// status code to log per message.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+
+		defer func() {
+			if r := recover(); r != nil {
+				klog.With(
+					klog.String("full_method", info.FullMethod),
+					klog.Any("panic", r),
+					klog.String("stacktrace", string(debug.Stack())),
+				).Error("panic recovered")
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		err = handler(srv, ss)
+
+		logRPC(ss.Context(), info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func logRPC(ctx context.Context, fullMethod string, start time.Time, err error) {
+	logger := klog.Ctx(ctx).With(
+		klog.String("full_method", fullMethod),
+		klog.Any("latency", time.Since(start)),
+		klog.String("code", status.Code(err).String()),
+	)
+
+	switch status.Code(err) {
+	case codes.OK:
+		logger.Info("rpc handled")
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		logger.Errorw("rpc failed", "error", err)
+	default:
+		logger.Warnw("rpc failed", "error", err)
+	}
+}
@@ -1,14 +1,21 @@
 package zap
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"time"
+
 	"github.com/stacktasec/circle/kit/klog/internal"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type Logger struct {
 	logger *zap.Logger
+	fields []internal.Field
+	level  zap.AtomicLevel
 }
 
 func NewLogger(opts ...internal.Option) *Logger {
@@ -32,25 +39,59 @@ func NewLogger(opts ...internal.Option) *Logger {
 		EncodeCaller:  zapcore.ShortCallerEncoder,
 	}
 
-	var encoding string
+	var encoder zapcore.Encoder
 	if o.Json {
-		encoding = "json"
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
 	} else {
-		encoding = "console"
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	atomicLevel := zap.NewAtomicLevelAt(convert(o.Level))
+	cores := []zapcore.Core{zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), atomicLevel)}
+
+	if o.FilePath != "" {
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(fileWriter(o.FilePath, o.Rotation)), atomicLevel))
+	}
+
+	for _, w := range o.Writers {
+		cores = append(cores, zapcore.NewCore(encoder, zapcore.AddSync(w), atomicLevel))
 	}
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(convert(o.Level)),
-		Encoding:         encoding,
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
+
+	core := zapcore.NewTee(cores...)
+	if o.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, o.SamplingInitial, o.SamplingThereafter)
 	}
 
-	logger, _ := config.Build(zap.AddCallerSkip(o.CallerSkip), zap.AddStacktrace(convert(o.Stacktrace)))
+	logger := zap.New(core, zap.AddCallerSkip(o.CallerSkip), zap.AddStacktrace(convert(o.Stacktrace)))
 
-	return &Logger{logger: logger}
+	return &Logger{logger: logger, level: atomicLevel}
 }
 
+// fileWriter opens path for the file sink, wrapping it in a lumberjack
+// roller when rotation is configured; otherwise it's a plain append-only
+// file.
+func fileWriter(path string, rotation *internal.Rotation) zapcore.WriteSyncer {
+	if rotation == nil {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			panic(err)
+		}
+		return f
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    rotation.MaxSizeMB,
+		MaxBackups: rotation.MaxBackups,
+		MaxAge:     rotation.MaxAgeDays,
+		Compress:   rotation.Compress,
+	}
+}
+
+// convert falls back to InfoLevel for anything it doesn't recognize instead
+// of panicking, since SetLevel is exported and can be called at runtime
+// with a value that was never checked by Options.Ensure (klog.LevelHandler
+// checks IsValidLevel first, but direct callers need not).
 func convert(level string) zapcore.Level {
 	switch level {
 	case internal.LevelDebug:
@@ -64,35 +105,106 @@ func convert(level string) zapcore.Level {
 	case internal.LevelFatal:
 		return zapcore.FatalLevel
 	default:
-		panic("can not convert")
+		return zapcore.InfoLevel
 	}
 }
 
+func (z *Logger) zapFields() []zap.Field {
+	fields := make([]zap.Field, len(z.fields))
+	for i, f := range z.fields {
+		fields[i] = zap.Any(f.Key, f.Value)
+	}
+	return fields
+}
+
 func (z *Logger) Debug(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	z.logger.Debug(msg)
+	z.logger.Debug(msg, z.zapFields()...)
 }
 
 func (z *Logger) Info(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	z.logger.Info(msg)
+	z.logger.Info(msg, z.zapFields()...)
 }
 
 func (z *Logger) Warn(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	z.logger.Warn(msg)
+	z.logger.Warn(msg, z.zapFields()...)
 }
 
 func (z *Logger) Error(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	z.logger.Error(msg)
+	z.logger.Error(msg, z.zapFields()...)
 }
 
 func (z *Logger) Fatal(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	z.logger.Fatal(msg)
+	z.logger.Fatal(msg, z.zapFields()...)
+}
+
+// keyValuesToFields turns an alternating key/value tail into zap.Fields,
+// skipping a trailing key with no paired value rather than panicking on it.
+func keyValuesToFields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, _ := keysAndValues[i].(string)
+		fields = append(fields, zap.Any(key, keysAndValues[i+1]))
+	}
+	return fields
+}
+
+func (z *Logger) Debugw(msg string, keysAndValues ...any) {
+	z.logger.Debug(msg, append(z.zapFields(), keyValuesToFields(keysAndValues)...)...)
+}
+
+func (z *Logger) Infow(msg string, keysAndValues ...any) {
+	z.logger.Info(msg, append(z.zapFields(), keyValuesToFields(keysAndValues)...)...)
+}
+
+func (z *Logger) Warnw(msg string, keysAndValues ...any) {
+	z.logger.Warn(msg, append(z.zapFields(), keyValuesToFields(keysAndValues)...)...)
+}
+
+func (z *Logger) Errorw(msg string, keysAndValues ...any) {
+	z.logger.Error(msg, append(z.zapFields(), keyValuesToFields(keysAndValues)...)...)
+}
+
+// With returns a Logger that also attaches fields to every line it logs.
+func (z *Logger) With(fields ...internal.Field) internal.Logger {
+	merged := make([]internal.Field, 0, len(z.fields)+len(fields))
+	merged = append(merged, z.fields...)
+	merged = append(merged, fields...)
+
+	return &Logger{logger: z.logger, fields: merged, level: z.level}
+}
+
+// Ctx attaches whatever fields were stashed on ctx via internal.ContextWithFields.
+func (z *Logger) Ctx(ctx context.Context) internal.Logger {
+	return z.With(internal.FieldsFromContext(ctx)...)
 }
 
 func (z *Logger) Sync() error {
 	return z.logger.Sync()
 }
+
+// SetLevel changes the level enforced by every core sharing this logger's
+// AtomicLevel, taking effect immediately for all Loggers derived from the
+// same NewLogger call (including those returned by With/Ctx).
+func (z *Logger) SetLevel(level string) {
+	z.level.SetLevel(convert(level))
+}
+
+func (z *Logger) Level() string {
+	switch z.level.Level() {
+	case zapcore.DebugLevel:
+		return internal.LevelDebug
+	case zapcore.WarnLevel:
+		return internal.LevelWarn
+	case zapcore.ErrorLevel:
+		return internal.LevelError
+	case zapcore.FatalLevel:
+		return internal.LevelFatal
+	default:
+		return internal.LevelInfo
+	}
+}
@@ -1,73 +1,52 @@
+// Package log is circle's original, public top-level logging package. No
+// code in this module imports it any more - internal/kit/zlog is the
+// internal equivalent actually used by internal/kit/core - but it keeps
+// forwarding to kit/klog.Logger so external importers of
+// github.com/stacktasec/circle/log keep compiling against their existing
+// call sites. That forwarding is a deliberate public API coupling: anyone
+// vendoring this package now also pulls in kit/klog (and its zap backend)
+// transitively. New code outside this package should depend on kit/klog
+// directly instead of through this shim.
 package log
 
 import (
 	"fmt"
-	"go.uber.org/zap"
+	"github.com/stacktasec/circle/kit/klog"
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.Logger
-
-func init() {
-	InitLogger()
-}
-
-func InitLogger(opts ...Option) {
-	o := &options{}
-
-	for _, opt := range opts {
-		opt.apply(o)
-	}
-
-	o.ensure()
-
-	encoderConfig := zapcore.EncoderConfig{
-		MessageKey:    "msg",
-		LevelKey:      "level",
-		TimeKey:       "time",
-		CallerKey:     "caller",
-		StacktraceKey: "stacktrace",
-		EncodeLevel:   zapcore.LowercaseColorLevelEncoder,
-		EncodeTime:    zapcore.ISO8601TimeEncoder,
-		EncodeCaller:  zapcore.ShortCallerEncoder,
-	}
-
-	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(*o.level),
-		Encoding:         "console",
-		EncoderConfig:    encoderConfig,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-	}
-
-	logger, _ = config.Build(zap.AddCallerSkip(2))
-}
-
 func Debug(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	logger.Debug(msg)
+	klog.Debug(format, a...)
 }
 
 func Info(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	logger.Info(msg)
+	klog.Info(format, a...)
 }
 
 func Warn(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	logger.Warn(msg)
+	klog.Warn(format, a...)
 }
 
 func Error(format any, a ...any) {
+	// an error that marshals itself (e.g. *circle.Error) is logged as a
+	// structured "error" field rather than flattened into the message.
+	if m, ok := format.(zapcore.ObjectMarshaler); ok && len(a) == 0 {
+		klog.With(klog.Any("error", m)).Error(format)
+		return
+	}
+	klog.Error(format, a...)
+}
+
+func Panic(format any, a ...any) {
 	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	logger.Error(msg)
+	klog.Error(msg)
+	panic(msg)
 }
 
 func Fatal(format any, a ...any) {
-	msg := fmt.Sprintf(fmt.Sprintf("%+v", format), a...)
-	logger.Fatal(msg)
+	klog.Fatal(format, a...)
 }
 
 func SyncLogger() error {
-	return logger.Sync()
+	return klog.SyncLogger()
 }